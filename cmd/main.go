@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,15 +12,19 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"grpc-auth/internal"
+	"grpc-auth/internal/core/services"
 	core "grpc-auth/internal/core/services/auth"
 	"grpc-auth/internal/infrastructure"
 	web "grpc-auth/internal/web/auth"
 	"grpc-auth/internal/web/interceptors"
+	"grpc-auth/internal/web/jwks"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 func main() {
@@ -40,11 +46,59 @@ func main() {
 	unitOfWorkStarter := infrastructure.NewPostgresUnitOfWorkStarter(pool)
 	timeProvider := infrastructure.NewRealTimeProvider()
 	uuidProvider := infrastructure.NewRealUuidProvider()
-	hasher := infrastructure.NewSha512Hasher()
+	argon2idHasher, err := infrastructure.NewArgon2idHasher(cfg.Auth.Argon2Memory, cfg.Auth.Argon2Iterations, cfg.Auth.Argon2Parallelism)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hasher := infrastructure.NewCompositeHasher(argon2idHasher, infrastructure.NewSha512Hasher())
 	salter := infrastructure.NewRealSalter()
-	jwtManager := infrastructure.NewRealJwtManager([]byte(cfg.Auth.Key))
+	pepperer, err := infrastructure.NewHmacPepperer(cfg.Auth.PepperId, cfg.Auth.Peppers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	usernamePolicy := infrastructure.NewRealUsernamePolicy(cfg.UsernamePolicy.MinLength, cfg.UsernamePolicy.MaxLength, cfg.UsernamePolicy.ReservedNames)
+	var bannedPasswords []string
+	if cfg.PasswordPolicy.BannedListPath != "" {
+		bannedPasswords, err = infrastructure.ReadBannedPasswords(cfg.PasswordPolicy.BannedListPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	passwordPolicy := infrastructure.NewRealPasswordPolicy(cfg.PasswordPolicy.MinLength, cfg.PasswordPolicy.MaxLength, cfg.PasswordPolicy.MinCharacterClasses, cfg.PasswordPolicy.MinEntropy, bannedPasswords)
+	var breachedPasswordChecker services.BreachedPasswordChecker = infrastructure.NewDisabledBreachedPasswordChecker()
+	if cfg.PasswordPolicy.BreachedCorpusPath != "" {
+		fileBreachedPasswordChecker, err := infrastructure.NewFileBreachedPasswordChecker(cfg.PasswordPolicy.BreachedCorpusPath, cfg.PasswordPolicy.BreachedThreshold)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer fileBreachedPasswordChecker.Close()
+		breachedPasswordChecker = fileBreachedPasswordChecker
+	}
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var jwtManager *infrastructure.RealJwtManager
+	if cfg.Auth.JwtMasterKey != "" {
+		jwtManager, err = NewRotatingJwtManager(backgroundCtx, cfg.Auth, unitOfWorkStarter, timeProvider, uuidProvider, logger)
+	} else {
+		jwtManager, err = NewJwtManager(cfg.Auth, timeProvider)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	service := core.NewRealService(cfg.Auth.AccessTokenLifetime, cfg.Auth.RefreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
+	clientAuthenticator, err := infrastructure.NewStaticClientAuthenticator(cfg.Auth.IntrospectionClients)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	revokedTokenCache := infrastructure.NewRealRevokedTokenCache(cfg.Auth.JwtLeeway, unitOfWorkStarter, timeProvider)
+	err = revokedTokenCache.Refresh(backgroundCtx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go revokedTokenCache.Run(backgroundCtx, cfg.Auth.RevokedTokensRefreshInterval, logger)
+
+	service := core.NewRealService(cfg.Auth.AccessTokenLifetime, cfg.Auth.RefreshTokenLifetime, cfg.Auth.PasswordChangeTokenLifetime, cfg.PasswordPolicy.MaxAge, cfg.PasswordPolicy.HistoryDepth, cfg.Auth.CheckAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	controller := web.NewController(service)
 
@@ -62,12 +116,47 @@ func main() {
 		}
 	}()
 
+	var jwksServer *http.Server
+	if cfg.JwksAddress != "" {
+		// Other instances publish a new key up to one rotation check interval after it is created, so a document served
+		// by them may lack it for that long
+		jwksMaxAge := max(cfg.Auth.JwtPropagationDelay-cfg.Auth.JwtRotationCheckInterval, 0)
+		jwksServer = jwks.NewServer(cfg.JwksAddress, jwks.NewHandler(service, jwksMaxAge, logger))
+
+		go func() {
+			logger.Info("Starting JWKS server on ", cfg.JwksAddress)
+			if err := jwksServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	var metricsServer *http.Server
+	if cfg.MetricsAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		go func() {
+			logger.Info("Starting metrics server on ", cfg.MetricsAddress)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 	<-signalChan
 
 	logger.Info("Shutting down gracefully...")
 	grpcServer.GracefulStop()
+	if jwksServer != nil {
+		_ = jwksServer.Shutdown(context.Background())
+	}
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(context.Background())
+	}
 }
 
 func NewLogger(level string) (*zap.SugaredLogger, error) {
@@ -94,6 +183,69 @@ func NewLogger(level string) (*zap.SugaredLogger, error) {
 	return logger.Sugar(), nil
 }
 
+func NewJwtManager(cfg internal.AuthConfig, timeProvider *infrastructure.RealTimeProvider) (*infrastructure.RealJwtManager, error) {
+	claimsPolicy := NewJwtClaimsPolicy(cfg)
+
+	if cfg.JwtKeyRingPath != "" {
+		activeKey, verificationKeys, err := infrastructure.ReadJwtKeyRing(cfg.JwtKeyRingPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return infrastructure.NewKeyRingJwtManager(activeKey, verificationKeys, claimsPolicy, timeProvider)
+	}
+
+	if cfg.JwtAlgorithm == "HS512" {
+		if cfg.Key == "" {
+			return nil, errors.New("AUTH_KEY is required for HS512")
+		}
+
+		return infrastructure.NewRealJwtManager([]byte(cfg.Key), claimsPolicy, timeProvider), nil
+	}
+
+	privateKeyPem, err := os.ReadFile(cfg.JwtPrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return infrastructure.NewAsymmetricJwtManager(cfg.JwtAlgorithm, privateKeyPem, claimsPolicy, timeProvider)
+}
+
+func NewJwtClaimsPolicy(cfg internal.AuthConfig) infrastructure.JwtClaimsPolicy {
+	return infrastructure.JwtClaimsPolicy{
+		Issuer:            cfg.JwtIssuer,
+		Audience:          cfg.JwtAudience,
+		Leeway:            cfg.JwtLeeway,
+		LegacyTokensUntil: cfg.JwtLegacyTokensUntil,
+	}
+}
+
+// NewRotatingJwtManager loads the stored signing keys, creating the first one if there is none,
+// and keeps rotating them in the background until the context is done.
+func NewRotatingJwtManager(ctx context.Context, cfg internal.AuthConfig, unitOfWorkStarter services.UnitOfWorkStarter, timeProvider *infrastructure.RealTimeProvider, uuidProvider *infrastructure.RealUuidProvider, logger *zap.SugaredLogger) (*infrastructure.RealJwtManager, error) {
+	// A replaced key must verify every token it signed, so it retires after the longest token lifetime
+	maxTokenLifetime := max(cfg.AccessTokenLifetime, cfg.PasswordChangeTokenLifetime)
+
+	rotator, err := infrastructure.NewJwtKeyRotator(cfg.JwtAlgorithm, cfg.JwtMasterKey, cfg.JwtRotationPeriod, cfg.JwtPropagationDelay, maxTokenLifetime, cfg.JwtLeeway, cfg.JwtRotationCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	activeKey, verificationKeys, err := rotator.Rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtManager, err := infrastructure.NewKeyRingJwtManager(activeKey, verificationKeys, NewJwtClaimsPolicy(cfg), timeProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	go rotator.Run(ctx, jwtManager, logger)
+
+	return jwtManager, nil
+}
+
 func NewPostgresConnectionPool(ctx context.Context, cfg internal.PostgreSqlConfig) (*pgxpool.Pool, error) {
 	connString := fmt.Sprintf(
 		`user=%s password=%s host=%s port=%d dbname=%s sslmode=%s 
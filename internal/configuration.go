@@ -3,16 +3,58 @@ package internal
 import "time"
 
 type AppConfig struct {
-	LogLevel   string `envconfig:"LOG_LEVEL" required:"true"`
-	GrpcAdress string `envconfig:"GRPC_ADDRESS" required:"true"`
-	Auth       AuthConfig
-	PostgreSQL PostgreSqlConfig
+	LogLevel       string `envconfig:"LOG_LEVEL" required:"true"`
+	GrpcAdress     string `envconfig:"GRPC_ADDRESS" required:"true"`
+	JwksAddress    string `envconfig:"JWKS_ADDRESS"`
+	MetricsAddress string `envconfig:"METRICS_ADDRESS"`
+	Auth           AuthConfig
+	UsernamePolicy UsernamePolicyConfig
+	PasswordPolicy PasswordPolicyConfig
+	PostgreSQL     PostgreSqlConfig
 }
 
 type AuthConfig struct {
-	Key                  string        `envconfig:"AUTH_KEY" required:"true"`
-	AccessTokenLifetime  time.Duration `envconfig:"AUTH_ACCESS_TOKEN_LIFETIME" required:"true"`
-	RefreshTokenLifetime time.Duration `envconfig:"AUTH_REFRESH_TOKEN_LIFETIME" required:"true"`
+	Key                          string            `envconfig:"AUTH_KEY"`
+	JwtAlgorithm                 string            `envconfig:"AUTH_JWT_ALGORITHM" default:"HS512"`
+	JwtPrivateKeyPath            string            `envconfig:"AUTH_JWT_PRIVATE_KEY_PATH"`
+	JwtKeyRingPath               string            `envconfig:"AUTH_JWT_KEY_RING_PATH"`
+	JwtMasterKey                 string            `envconfig:"AUTH_JWT_MASTER_KEY"`
+	JwtRotationPeriod            time.Duration     `envconfig:"AUTH_JWT_ROTATION_PERIOD" default:"720h"`
+	JwtPropagationDelay          time.Duration     `envconfig:"AUTH_JWT_PROPAGATION_DELAY" default:"1h"`
+	JwtRotationCheckInterval     time.Duration     `envconfig:"AUTH_JWT_ROTATION_CHECK_INTERVAL" default:"1m"`
+	JwtIssuer                    string            `envconfig:"AUTH_JWT_ISSUER" default:"grpc-auth"`
+	JwtAudience                  string            `envconfig:"AUTH_JWT_AUDIENCE" default:"grpc-auth"`
+	JwtLeeway                    time.Duration     `envconfig:"AUTH_JWT_LEEWAY" default:"30s"`
+	JwtLegacyTokensUntil         time.Time         `envconfig:"AUTH_JWT_LEGACY_TOKENS_UNTIL"`
+	RevokedTokensRefreshInterval time.Duration     `envconfig:"AUTH_REVOKED_TOKENS_REFRESH_INTERVAL" default:"10s"`
+	AccessTokenLifetime          time.Duration     `envconfig:"AUTH_ACCESS_TOKEN_LIFETIME" required:"true"`
+	RefreshTokenLifetime         time.Duration     `envconfig:"AUTH_REFRESH_TOKEN_LIFETIME" required:"true"`
+	PasswordChangeTokenLifetime  time.Duration     `envconfig:"AUTH_PASSWORD_CHANGE_TOKEN_LIFETIME" default:"10m"`
+	CheckAccessTokenSession      bool              `envconfig:"AUTH_CHECK_ACCESS_TOKEN_SESSION" default:"false"`
+	Argon2Memory                 uint32            `envconfig:"AUTH_ARGON2_MEMORY" default:"65536"`
+	Argon2Iterations             uint32            `envconfig:"AUTH_ARGON2_ITERATIONS" default:"3"`
+	Argon2Parallelism            uint8             `envconfig:"AUTH_ARGON2_PARALLELISM" default:"2"`
+	PepperId                     string            `envconfig:"AUTH_PEPPER_ID"`
+	Peppers                      map[string]string `envconfig:"AUTH_PEPPERS"`
+	IntrospectionClients         map[string]string `envconfig:"AUTH_INTROSPECTION_CLIENTS"`
+}
+
+type UsernamePolicyConfig struct {
+	MinLength     int      `envconfig:"USERNAME_MIN_LENGTH" default:"3"`
+	MaxLength     int      `envconfig:"USERNAME_MAX_LENGTH" default:"32"`
+	ReservedNames []string `envconfig:"USERNAME_RESERVED_NAMES" default:"admin,administrator,root,system,support,moderator,superuser"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength           int           `envconfig:"PASSWORD_MIN_LENGTH" default:"8"`
+	MaxLength           int           `envconfig:"PASSWORD_MAX_LENGTH" default:"128"`
+	MinCharacterClasses int           `envconfig:"PASSWORD_MIN_CHARACTER_CLASSES" default:"3"`
+	MinEntropy          float64       `envconfig:"PASSWORD_MIN_ENTROPY" default:"40"`
+	BannedListPath      string        `envconfig:"PASSWORD_BANNED_LIST_PATH"`
+	BreachedCorpusPath  string        `envconfig:"PASSWORD_BREACHED_CORPUS_PATH"`
+	BreachedThreshold   int           `envconfig:"PASSWORD_BREACHED_THRESHOLD" default:"0"`
+	HistoryDepth        int           `envconfig:"PASSWORD_HISTORY_DEPTH" default:"5"`
+	MaxAge              time.Duration `envconfig:"PASSWORD_MAX_AGE" default:"0"`
 }
 
 type PostgreSqlConfig struct {
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordHistoryEntry struct {
+	Uuid      uuid.UUID
+	UserUuid  uuid.UUID
+	CreatedAt time.Time
+	Password  string
+	Salt      string
+	PepperId  string
+}
+
+func NewPasswordHistoryEntry(uuid, userUuid uuid.UUID, createdAt time.Time, password, salt, pepperId string) *PasswordHistoryEntry {
+	return &PasswordHistoryEntry{uuid, userUuid, createdAt, password, salt, pepperId}
+}
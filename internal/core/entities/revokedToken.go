@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RevokedToken is an access token rejected before its expiration. It is kept until the token would have expired anyway.
+type RevokedToken struct {
+	TokenUuid    uuid.UUID
+	UserUuid     uuid.UUID
+	RevokedAt    time.Time
+	ExpirationAt time.Time
+}
+
+func NewRevokedToken(tokenUuid, userUuid uuid.UUID, revokedAt, expirationAt time.Time) *RevokedToken {
+	return &RevokedToken{tokenUuid, userUuid, revokedAt, expirationAt}
+}
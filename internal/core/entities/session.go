@@ -9,8 +9,13 @@ type Session struct {
 	RefreshToken uuid.UUID
 	UserUuid     uuid.UUID
 	ExpirationAt time.Time
+	Uuid         uuid.UUID
+	CreatedAt    time.Time
+	RefreshedAt  time.Time
+	ClientIp     string
+	UserAgent    string
 }
 
-func NewSession(refreshToken, userUuid uuid.UUID, expirationAt time.Time) *Session {
-	return &Session{refreshToken, userUuid, expirationAt}
+func NewSession(refreshToken, userUuid uuid.UUID, expirationAt time.Time, uuid uuid.UUID, createdAt, refreshedAt time.Time, clientIp, userAgent string) *Session {
+	return &Session{refreshToken, userUuid, expirationAt, uuid, createdAt, refreshedAt, clientIp, userAgent}
 }
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+type SigningKey struct {
+	Id           string
+	Algorithm    string
+	EncryptedKey []byte
+	CreatedAt    time.Time
+	ActivatesAt  time.Time
+	RetiresAt    time.Time // Zero until a newer key replaces this one
+}
+
+func NewSigningKey(id, algorithm string, encryptedKey []byte, createdAt, activatesAt, retiresAt time.Time) *SigningKey {
+	return &SigningKey{id, algorithm, encryptedKey, createdAt, activatesAt, retiresAt}
+}
@@ -6,12 +6,18 @@ import (
 )
 
 type User struct {
-	Uuid      uuid.UUID
-	CreatedAt time.Time
-	Name      string
-	Password  string
+	Uuid           uuid.UUID
+	CreatedAt      time.Time
+	Name           string
+	NormalizedName string
+	Password       string
+	Salt           string
+	PepperId       string
+
+	PasswordChangedAt time.Time
+	TokensValidAfter  time.Time // Access tokens issued before are rejected, zero while all tokens are accepted
 }
 
-func NewUser(uuid uuid.UUID, createdAt time.Time, name, password string) *User {
-	return &User{uuid, createdAt, name, password}
+func NewUser(uuid uuid.UUID, createdAt time.Time, name, normalizedName, password, salt, pepperId string, passwordChangedAt, tokensValidAfter time.Time) *User {
+	return &User{uuid, createdAt, name, normalizedName, password, salt, pepperId, passwordChangedAt, tokensValidAfter}
 }
@@ -5,17 +5,67 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Name, Password string
+	Name, Password      string
+	ClientIp, UserAgent string
 }
 
 type DeleteUserRequest struct {
 	AccessToken string
 }
 
-type RefreshTokensRequest struct {
+type DeleteSessionRequest struct {
 	RefreshToken string
 }
 
+type DeleteAllSessionsRequest struct {
+	AccessToken, RefreshToken string
+}
+
+type ListSessionsRequest struct {
+	AccessToken string
+}
+
+type DeleteSessionByIdRequest struct {
+	AccessToken, SessionId string
+}
+
+type ChangeLoginRequest struct {
+	AccessToken, NewName, Password string
+}
+
+type ChangePasswordRequest struct {
+	AccessToken, CurrentPassword, NewPassword string
+	RevokeOtherSessions                       bool
+	RefreshToken                              string
+}
+
+type RefreshTokensRequest struct {
+	RefreshToken        string
+	ClientIp, UserAgent string
+}
+
 type CheckAccessTokenRequest struct {
 	AccessToken string
 }
+
+type GetCurrentUserRequest struct {
+	AccessToken string
+}
+
+type RevokeAccessTokenRequest struct {
+	AccessToken string
+}
+
+type RevokeAllAccessTokensRequest struct {
+	AccessToken string
+}
+
+// IntrospectRequest takes an access or a refresh token, the format tells them apart.
+// ClientId and ClientSecret authenticate the calling resource server.
+type IntrospectRequest struct {
+	Token        string
+	ClientId     string
+	ClientSecret string
+}
+
+type GetJwksRequest struct{}
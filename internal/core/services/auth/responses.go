@@ -1,17 +1,53 @@
 package auth
 
+import (
+	"grpc-auth/internal/core/value-objects"
+	"time"
+)
+
 type RegisterResponse struct {
 	Message string
 }
 
 type LoginResponse struct {
 	RefreshToken, AccessToken string
+	PasswordExpired           bool
 }
 
 type DeleteUserResponse struct {
 	Message string
 }
 
+type DeleteSessionResponse struct {
+	Message string
+}
+
+type DeleteAllSessionsResponse struct {
+	Message string
+}
+
+type SessionInfo struct {
+	Id                                   string
+	CreatedAt, RefreshedAt, ExpirationAt time.Time
+	ClientIp, UserAgent                  string
+}
+
+type ListSessionsResponse struct {
+	Sessions []SessionInfo
+}
+
+type DeleteSessionByIdResponse struct {
+	Message string
+}
+
+type ChangeLoginResponse struct {
+	Message string
+}
+
+type ChangePasswordResponse struct {
+	Message string
+}
+
 type RefreshTokensResponse struct {
 	RefreshToken, AccessToken string
 }
@@ -19,3 +55,40 @@ type RefreshTokensResponse struct {
 type CheckAccessTokenResponse struct {
 	IsActive bool
 }
+
+type GetCurrentUserResponse struct {
+	Uuid, Name string
+	CreatedAt  time.Time
+}
+
+type RevokeAccessTokenResponse struct {
+	Message string
+}
+
+type RevokeAllAccessTokensResponse struct {
+	Message string
+}
+
+// Token types reported by Introspect, named like the token type hints of RFC 7009.
+const (
+	AccessTokenType  = "access_token"
+	RefreshTokenType = "refresh_token"
+)
+
+// IntrospectResponse follows RFC 7662, all fields but Active are empty for inactive tokens.
+// Scope is always empty, tokens restricted to the password change are inactive. ClientId is always empty too,
+// tokens are issued to users rather than to clients.
+type IntrospectResponse struct {
+	Active       bool
+	Subject      string
+	ExpirationAt time.Time
+	IssuedAt     time.Time
+	Scope        string
+	ClientId     string
+	TokenType    string
+	Session      *SessionInfo // Nil for tokens without a session
+}
+
+type GetJwksResponse struct {
+	Keys []value_objects.Jwk
+}
@@ -2,29 +2,60 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"grpc-auth/internal/core/entities"
 	"grpc-auth/internal/core/services"
 	"grpc-auth/internal/core/value-objects"
+	"sync"
 	"time"
 )
 
 type RealService struct {
-	accessTokenLifetime  time.Duration
-	refreshTokenLifetime time.Duration
-	unitOfWorkStarter    services.UnitOfWorkStarter
-	timeProvider         services.TimeProvider
-	uuidProvider         services.UuidProvider
-	hasher               services.Hasher
-	salter               services.Salter
-	jwtManager           services.JwtManager
+	accessTokenLifetime         time.Duration
+	refreshTokenLifetime        time.Duration
+	passwordChangeTokenLifetime time.Duration
+	maxPasswordAge              time.Duration
+	passwordHistoryDepth        int
+	checkAccessTokenSession     bool
+	unitOfWorkStarter           services.UnitOfWorkStarter
+	timeProvider                services.TimeProvider
+	uuidProvider                services.UuidProvider
+	hasher                      services.Hasher
+	salter                      services.Salter
+	pepperer                    services.Pepperer
+	usernamePolicy              services.UsernamePolicy
+	passwordPolicy              services.PasswordPolicy
+	breachedPasswordChecker     services.BreachedPasswordChecker
+	jwtManager                  services.JwtManager
+	revokedTokenCache           services.RevokedTokenCache
+	clientAuthenticator         services.ClientAuthenticator
+	dummyPassword               *dummyPassword
 }
 
-func NewRealService(accessTokenLifetime, refreshTokenLifetime time.Duration, unitOfWorkStarter services.UnitOfWorkStarter, timeProvider services.TimeProvider, uuidProvider services.UuidProvider, hasher services.Hasher, salter services.Salter, jwtManager services.JwtManager) *RealService {
-	return &RealService{accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager}
+// dummyPassword is verified instead of a password of an unknown user, so Login takes as long for an unknown name as for
+// a known one. Its hash is made on first use with the parameters of the hasher, so only the very first such login is slower.
+type dummyPassword struct {
+	once sync.Once
+	hash string
+}
+
+func NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge time.Duration, passwordHistoryDepth int, checkAccessTokenSession bool, unitOfWorkStarter services.UnitOfWorkStarter, timeProvider services.TimeProvider, uuidProvider services.UuidProvider, hasher services.Hasher, salter services.Salter, pepperer services.Pepperer, usernamePolicy services.UsernamePolicy, passwordPolicy services.PasswordPolicy, breachedPasswordChecker services.BreachedPasswordChecker, jwtManager services.JwtManager, revokedTokenCache services.RevokedTokenCache, clientAuthenticator services.ClientAuthenticator) *RealService {
+	return &RealService{accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator, &dummyPassword{}}
 }
 
 func (s *RealService) Register(ctx context.Context, request *RegisterRequest) (*RegisterResponse, error) {
+	err := s.checkName(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkPassword(request.Name, request.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
 	if err != nil {
 		return nil, err
@@ -34,10 +65,8 @@ func (s *RealService) Register(ctx context.Context, request *RegisterRequest) (*
 	userUuid := s.uuidProvider.Random()
 	createdAt := s.timeProvider.Now()
 
-	saltedPassword := s.salter.Salt(userUuid, createdAt, request.Name, request.Password)
-	hashOfSaltedPassword := s.hasher.Hash(saltedPassword)
-
-	user := entities.NewUser(userUuid, createdAt, request.Name, hashOfSaltedPassword)
+	user := entities.NewUser(userUuid, createdAt, request.Name, s.usernamePolicy.Normalize(request.Name), "", "", "", createdAt, time.Time{})
+	s.setPassword(user, request.Password)
 
 	ok, err := userRepository.TryCreate(ctx, user)
 	if err != nil {
@@ -67,29 +96,62 @@ func (s *RealService) Login(ctx context.Context, request *LoginRequest) (*LoginR
 	userRepository := unitOfWork.UserRepository()
 	sessionRepository := unitOfWork.SessionRepository()
 
-	user, err := userRepository.TryGetByName(ctx, request.Name)
+	user, err := userRepository.TryGetByName(ctx, s.usernamePolicy.Normalize(request.Name))
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
 	if user == nil {
+		s.verifyDummyPassword(request.Password)
+
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, &services.InvariantViolationError{Message: "login or/and password is invalid"}
 	}
 
-	saltedPassword := s.salter.Salt(user.Uuid, user.CreatedAt, user.Name, request.Password)
-	hashOfSaltedPassword := s.hasher.Hash(saltedPassword)
-	if user.Password != hashOfSaltedPassword {
+	if !s.verifyPassword(user, request.Password) {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, &services.InvariantViolationError{Message: "login or/and password is invalid"}
 	}
 
+	// The plain password is only known here, so outdated salts, peppers and hashes are upgraded on login
+	if s.passwordNeedsUpgrade(user) {
+		s.setPassword(user, request.Password)
+
+		_, err = userRepository.TryUpdate(ctx, user)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+	}
+
 	now := s.timeProvider.Now()
 
-	authInfo := &value_objects.AuthInfo{UserUuid: user.Uuid, ExpirationAt: now.Add(s.accessTokenLifetime)}
+	// No session is created for an expired password, the restricted token only allows to change it
+	if s.passwordIsExpired(user, now) {
+		authInfo := &value_objects.AuthInfo{UserUuid: user.Uuid, ExpirationAt: now.Add(s.passwordChangeTokenLifetime), Scope: value_objects.PasswordChangeScope, TokenUuid: s.uuidProvider.Random()}
+		accessToken, err := s.jwtManager.Generate(authInfo)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+
+		err = unitOfWork.Save(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &LoginResponse{"", accessToken, true}, nil
+	}
+
+	refreshToken := s.uuidProvider.Random()
+	sessionUuid := s.uuidProvider.Random()
+
+	authInfo := &value_objects.AuthInfo{UserUuid: user.Uuid, ExpirationAt: now.Add(s.accessTokenLifetime), TokenUuid: s.uuidProvider.Random(), SessionUuid: sessionUuid}
 	accessToken, err := s.jwtManager.Generate(authInfo)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
@@ -97,8 +159,7 @@ func (s *RealService) Login(ctx context.Context, request *LoginRequest) (*LoginR
 		return nil, err
 	}
 
-	refreshToken := s.uuidProvider.Random()
-	session := entities.NewSession(refreshToken, user.Uuid, now.Add(s.refreshTokenLifetime))
+	session := entities.NewSession(refreshToken, user.Uuid, now.Add(s.refreshTokenLifetime), sessionUuid, now, now, request.ClientIp, request.UserAgent)
 
 	err = sessionRepository.Create(ctx, session)
 	if err != nil {
@@ -112,17 +173,13 @@ func (s *RealService) Login(ctx context.Context, request *LoginRequest) (*LoginR
 		return nil, err
 	}
 
-	return &LoginResponse{refreshToken.String(), accessToken}, nil
+	return &LoginResponse{refreshToken.String(), accessToken, false}, nil
 }
 
 func (s *RealService) DeleteUser(ctx context.Context, request *DeleteUserRequest) (*DeleteUserResponse, error) {
-	authInfo := s.jwtManager.Parse(request.AccessToken)
-	if authInfo == nil {
-		return nil, &services.InvariantViolationError{Message: "access token is invalid"}
-	}
-
-	if authInfo.ExpirationAt.Before(s.timeProvider.Now()) {
-		return nil, &services.InvariantViolationError{Message: "access token is expired"}
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
 	}
 
 	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
@@ -131,6 +188,13 @@ func (s *RealService) DeleteUser(ctx context.Context, request *DeleteUserRequest
 	}
 	userRepository := unitOfWork.UserRepository()
 
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
 	deleted, err := userRepository.TryDelete(ctx, authInfo.UserUuid)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
@@ -152,82 +216,187 @@ func (s *RealService) DeleteUser(ctx context.Context, request *DeleteUserRequest
 	return &DeleteUserResponse{"user deleted"}, nil
 }
 
-func (s *RealService) RefreshTokens(ctx context.Context, request *RefreshTokensRequest) (*RefreshTokensResponse, error) {
+func (s *RealService) DeleteSession(ctx context.Context, request *DeleteSessionRequest) (*DeleteSessionResponse, error) {
+	refreshToken, err := uuid.Parse(request.RefreshToken)
+	if err != nil {
+		return nil, &services.InvariantViolationError{Message: "refresh token format is invalid"}
+	}
+
 	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
 	if err != nil {
 		return nil, err
 	}
 	sessionRepository := unitOfWork.SessionRepository()
 
-	refreshToken, err := uuid.Parse(request.RefreshToken)
+	session, err := sessionRepository.TryGetByRefreshToken(ctx, refreshToken)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
-		return nil, &services.InvariantViolationError{Message: "refresh token format is invalid"}
+		return nil, err
 	}
+	if session == nil {
+		_ = unitOfWork.Rollback(ctx)
 
-	session, err := sessionRepository.TryGetByRefreshToken(ctx, refreshToken)
+		return nil, &services.InvariantViolationError{Message: "refresh token does not exists"}
+	}
+
+	err = sessionRepository.DeleteByRefreshToken(ctx, refreshToken)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
-	if session == nil {
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeleteSessionResponse{"session deleted"}, nil
+}
+
+func (s *RealService) DeleteAllSessions(ctx context.Context, request *DeleteAllSessionsRequest) (*DeleteAllSessionsResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	keptRefreshToken, err := parseKeptRefreshToken(request.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
-		return nil, &services.InvariantViolationError{Message: "refresh token does not exists"}
+		return nil, err
 	}
 
-	err = sessionRepository.DeleteByRefreshToken(ctx, refreshToken)
+	err = sessionRepository.DeleteByUserUuid(ctx, authInfo.UserUuid, keptRefreshToken)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
 
-	now := s.timeProvider.Now()
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	if session.ExpirationAt.Before(now) {
+	return &DeleteAllSessionsResponse{"sessions deleted"}, nil
+}
+
+func (s *RealService) ListSessions(ctx context.Context, request *ListSessionsRequest) (*ListSessionsResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
-		return nil, &services.InvariantViolationError{Message: "refresh token expired"}
+		return nil, err
 	}
 
-	refreshToken = s.uuidProvider.Random()
+	sessions, err := sessionRepository.GetAllByUserUuid(ctx, authInfo.UserUuid)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
 
-	session = entities.NewSession(refreshToken, session.UserUuid, now.Add(s.refreshTokenLifetime))
+		return nil, err
+	}
 
-	err = sessionRepository.Create(ctx, session)
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.timeProvider.Now()
+
+	// The refresh token is a credential, so sessions are exposed only by their own uuid
+	sessionInfos := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ExpirationAt.Before(now) {
+			continue
+		}
+
+		sessionInfos = append(sessionInfos, *newSessionInfo(session))
+	}
+
+	return &ListSessionsResponse{sessionInfos}, nil
+}
+
+func (s *RealService) DeleteSessionById(ctx context.Context, request *DeleteSessionByIdRequest) (*DeleteSessionByIdResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionUuid, err := uuid.Parse(request.SessionId)
+	if err != nil {
+		return nil, &services.InvariantViolationError{Message: "session id format is invalid"}
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
 
-	authInfo := &value_objects.AuthInfo{UserUuid: session.UserUuid, ExpirationAt: now.Add(s.accessTokenLifetime)}
-	accessToken, err := s.jwtManager.Generate(authInfo)
+	deleted, err := sessionRepository.TryDeleteByUuid(ctx, sessionUuid, authInfo.UserUuid)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
 
+	if !deleted {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "session not found"}
+	}
+
 	err = unitOfWork.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &RefreshTokensResponse{refreshToken.String(), accessToken}, nil
+	return &DeleteSessionByIdResponse{"session deleted"}, nil
 }
 
-func (s *RealService) CheckAccessToken(ctx context.Context, request *CheckAccessTokenRequest) (*CheckAccessTokenResponse, error) {
-	authInfo := s.jwtManager.Parse(request.AccessToken)
-	if authInfo == nil {
-		return nil, &services.InvariantViolationError{Message: "access token is invalid"}
+func (s *RealService) ChangeLogin(ctx context.Context, request *ChangeLoginRequest) (*ChangeLoginResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
 	}
 
-	if authInfo.ExpirationAt.Before(s.timeProvider.Now()) {
-		return &CheckAccessTokenResponse{false}, nil
+	err = s.checkName(request.NewName)
+	if err != nil {
+		return nil, err
 	}
 
 	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
@@ -236,23 +405,763 @@ func (s *RealService) CheckAccessToken(ctx context.Context, request *CheckAccess
 	}
 	userRepository := unitOfWork.UserRepository()
 
-	exists, err := userRepository.Exists(ctx, authInfo.UserUuid)
+	user, err := userRepository.TryGetByUuid(ctx, authInfo.UserUuid)
 	if err != nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, err
 	}
+	if user == nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "user not found"}
+	}
 
-	if !exists {
+	err = checkIssuedAfter(authInfo, user.TokensValidAfter)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	if !s.verifyPassword(user, request.Password) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "password is invalid"}
+	}
+
+	// The legacy salt depends on the name, so such a password has to be rehashed before the rename
+	if user.Salt == "" {
+		s.setPassword(user, request.Password)
+	}
+	user.Name = request.NewName
+	user.NormalizedName = s.usernamePolicy.Normalize(request.NewName)
+
+	ok, err := userRepository.TryUpdate(ctx, user)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if !ok {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.ConflictError{Message: "login is already taken"}
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangeLoginResponse{"login changed"}, nil
+}
+
+func (s *RealService) ChangePassword(ctx context.Context, request *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	authInfo, err := s.parseAccessToken(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	keptRefreshToken, err := parseKeptRefreshToken(request.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+	passwordHistoryRepository := unitOfWork.PasswordHistoryRepository()
+
+	user, err := userRepository.TryGetByUuid(ctx, authInfo.UserUuid)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if user == nil {
 		_ = unitOfWork.Rollback(ctx)
 
 		return nil, &services.InvariantViolationError{Message: "user not found"}
 	}
 
+	err = checkIssuedAfter(authInfo, user.TokensValidAfter)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	if !s.verifyPassword(user, request.CurrentPassword) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "password is invalid"}
+	}
+
+	err = s.checkPassword(user.Name, request.NewPassword)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	if s.passwordHistoryDepth > 0 {
+		// The current password is one of the last passwords too, so the history keeps one entry less than the depth
+		history, err := passwordHistoryRepository.GetLatestByUserUuid(ctx, user.Uuid, s.passwordHistoryDepth-1)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+
+		if s.isPasswordReused(user, history, request.NewPassword) {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, &services.PolicyError{Field: "password", Violations: []value_objects.Violation{{
+				Reason:      "PASSWORD_REUSED",
+				Description: fmt.Sprintf("password must differ from the last %d passwords", s.passwordHistoryDepth),
+			}}}
+		}
+	}
+
+	// The current password is hashed anew, so history entries never depend on legacy salts or retired peppers
+	previousPassword, previousSalt, previousPepperId := s.hashPassword(request.CurrentPassword)
+
+	s.setPassword(user, request.NewPassword)
+	user.PasswordChangedAt = s.timeProvider.Now()
+	// Access tokens issued with the old password must not outlive it
+	user.TokensValidAfter = user.PasswordChangedAt
+
+	_, err = userRepository.TryUpdate(ctx, user)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	if s.passwordHistoryDepth > 0 {
+		if s.passwordHistoryDepth > 1 {
+			entry := entities.NewPasswordHistoryEntry(s.uuidProvider.Random(), user.Uuid, s.timeProvider.Now(), previousPassword, previousSalt, previousPepperId)
+
+			err = passwordHistoryRepository.Create(ctx, entry)
+			if err != nil {
+				_ = unitOfWork.Rollback(ctx)
+
+				return nil, err
+			}
+		}
+
+		err = passwordHistoryRepository.DeleteAllButLatestByUserUuid(ctx, user.Uuid, s.passwordHistoryDepth-1)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+	}
+
+	if request.RevokeOtherSessions {
+		err = sessionRepository.DeleteByUserUuid(ctx, user.Uuid, keptRefreshToken)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+	}
+
 	err = unitOfWork.Save(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &CheckAccessTokenResponse{true}, nil
+	return &ChangePasswordResponse{"password changed"}, nil
+}
+
+func (s *RealService) RefreshTokens(ctx context.Context, request *RefreshTokensRequest) (*RefreshTokensResponse, error) {
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+
+	refreshToken, err := uuid.Parse(request.RefreshToken)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "refresh token format is invalid"}
+	}
+
+	session, err := sessionRepository.TryGetByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if session == nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "refresh token does not exists"}
+	}
+
+	err = sessionRepository.DeleteByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	now := s.timeProvider.Now()
+
+	if session.ExpirationAt.Before(now) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "refresh token expired"}
+	}
+
+	user, err := userRepository.TryGetByUuid(ctx, session.UserUuid)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if user == nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "user not found"}
+	}
+
+	// Otherwise refreshing would keep a session alive forever without ever changing the expired password
+	if s.passwordIsExpired(user, now) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "password is expired, log in to change it"}
+	}
+
+	refreshToken = s.uuidProvider.Random()
+
+	session = entities.NewSession(refreshToken, session.UserUuid, now.Add(s.refreshTokenLifetime), session.Uuid, session.CreatedAt, now, request.ClientIp, request.UserAgent)
+
+	err = sessionRepository.Create(ctx, session)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	authInfo := &value_objects.AuthInfo{UserUuid: session.UserUuid, ExpirationAt: now.Add(s.accessTokenLifetime), TokenUuid: s.uuidProvider.Random(), SessionUuid: session.Uuid}
+	accessToken, err := s.jwtManager.Generate(authInfo)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshTokensResponse{refreshToken.String(), accessToken}, nil
+}
+
+func (s *RealService) CheckAccessToken(ctx context.Context, request *CheckAccessTokenRequest) (*CheckAccessTokenResponse, error) {
+	authInfo, err := s.jwtManager.Parse(request.AccessToken)
+	var tokenError *services.TokenError
+	if errors.As(err, &tokenError) && tokenError.Reason == services.TokenExpired {
+		return &CheckAccessTokenResponse{false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo.Scope != "" || s.isRevoked(authInfo) {
+		return &CheckAccessTokenResponse{false}, nil
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	if errors.As(err, &tokenError) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return &CheckAccessTokenResponse{false}, nil
+	}
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	// Deleting a session ends the access tokens issued for it too. Tokens without a session id are not bound to one
+	if s.checkAccessTokenSession && authInfo.SessionUuid != uuid.Nil {
+		sessionRepository := unitOfWork.SessionRepository()
+
+		exists, err := sessionRepository.ExistsByUuid(ctx, authInfo.SessionUuid)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+
+		if !exists {
+			_ = unitOfWork.Rollback(ctx)
+
+			return &CheckAccessTokenResponse{false}, nil
+		}
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckAccessTokenResponse{true}, nil
+}
+
+func (s *RealService) GetCurrentUser(ctx context.Context, request *GetCurrentUserRequest) (*GetCurrentUserResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+
+	user, err := userRepository.TryGetByUuid(ctx, authInfo.UserUuid)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if user == nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "user not found"}
+	}
+
+	err = checkIssuedAfter(authInfo, user.TokensValidAfter)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCurrentUserResponse{user.Uuid.String(), user.Name, user.CreatedAt}, nil
+}
+
+func (s *RealService) RevokeAccessToken(ctx context.Context, request *RevokeAccessTokenRequest) (*RevokeAccessTokenResponse, error) {
+	authInfo, err := s.parseAccessToken(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo.TokenUuid == uuid.Nil {
+		return nil, &services.InvariantViolationError{Message: "access token has no id and cannot be revoked"}
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	revokedTokenRepository := unitOfWork.RevokedTokenRepository()
+
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	revokedToken := entities.NewRevokedToken(authInfo.TokenUuid, authInfo.UserUuid, s.timeProvider.Now(), authInfo.ExpirationAt)
+
+	err = revokedTokenRepository.Create(ctx, revokedToken)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.revokedTokenCache.Add(revokedToken.TokenUuid, revokedToken.ExpirationAt)
+
+	return &RevokeAccessTokenResponse{"access token revoked"}, nil
+}
+
+func (s *RealService) RevokeAllAccessTokens(ctx context.Context, request *RevokeAllAccessTokensRequest) (*RevokeAllAccessTokensResponse, error) {
+	authInfo, err := s.authenticate(request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+
+	user, err := userRepository.TryGetByUuid(ctx, authInfo.UserUuid)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+	if user == nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, &services.InvariantViolationError{Message: "user not found"}
+	}
+
+	err = checkIssuedAfter(authInfo, user.TokensValidAfter)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	user.TokensValidAfter = s.timeProvider.Now()
+
+	_, err = userRepository.TryUpdate(ctx, user)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevokeAllAccessTokensResponse{"access tokens revoked"}, nil
+}
+
+// Introspect reports invalid, expired and revoked tokens as inactive instead of failing, as RFC 7662 requires.
+// Access tokens are judged like CheckAccessToken does, so tokens restricted to the password change are inactive too.
+// Only registered clients may call it, otherwise anyone could probe tokens for validity and learn their metadata.
+func (s *RealService) Introspect(ctx context.Context, request *IntrospectRequest) (*IntrospectResponse, error) {
+	if !s.clientAuthenticator.Authenticate(request.ClientId, request.ClientSecret) {
+		return nil, &services.TokenError{Reason: services.ClientUnauthenticated, Message: "client credentials are invalid"}
+	}
+
+	// Refresh tokens are UUIDs, which are never valid JWTs
+	refreshToken, err := uuid.Parse(request.Token)
+	if err == nil {
+		return s.introspectRefreshToken(ctx, refreshToken)
+	}
+
+	return s.introspectAccessToken(ctx, request.Token)
+}
+
+func (s *RealService) introspectAccessToken(ctx context.Context, accessToken string) (*IntrospectResponse, error) {
+	authInfo, err := s.jwtManager.Parse(accessToken)
+	var tokenError *services.TokenError
+	if errors.As(err, &tokenError) {
+		return &IntrospectResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo.Scope != "" || s.isRevoked(authInfo) {
+		return &IntrospectResponse{}, nil
+	}
+
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := unitOfWork.UserRepository()
+	sessionRepository := unitOfWork.SessionRepository()
+
+	// Tokens of deleted users are inactive too
+	err = s.checkTokensValidAfter(ctx, userRepository, authInfo)
+	var invariantViolationError *services.InvariantViolationError
+	if errors.As(err, &tokenError) || errors.As(err, &invariantViolationError) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return &IntrospectResponse{}, nil
+	}
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	var session *entities.Session
+	if authInfo.SessionUuid != uuid.Nil {
+		session, err = sessionRepository.TryGetByUuid(ctx, authInfo.SessionUuid)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, err
+		}
+
+		if session == nil && s.checkAccessTokenSession {
+			_ = unitOfWork.Rollback(ctx)
+
+			return &IntrospectResponse{}, nil
+		}
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IntrospectResponse{
+		Active:       true,
+		Subject:      authInfo.UserUuid.String(),
+		ExpirationAt: authInfo.ExpirationAt,
+		IssuedAt:     authInfo.IssuedAt,
+		TokenType:    AccessTokenType,
+		Session:      newSessionInfo(session),
+	}, nil
+}
+
+func (s *RealService) introspectRefreshToken(ctx context.Context, refreshToken uuid.UUID) (*IntrospectResponse, error) {
+	unitOfWork, err := s.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessionRepository := unitOfWork.SessionRepository()
+
+	session, err := sessionRepository.TryGetByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, err
+	}
+
+	if session == nil || session.ExpirationAt.Before(s.timeProvider.Now()) {
+		_ = unitOfWork.Rollback(ctx)
+
+		return &IntrospectResponse{}, nil
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every refresh replaces the refresh token, so the current one was issued at the last refresh
+	return &IntrospectResponse{
+		Active:       true,
+		Subject:      session.UserUuid.String(),
+		ExpirationAt: session.ExpirationAt,
+		IssuedAt:     session.RefreshedAt,
+		TokenType:    RefreshTokenType,
+		Session:      newSessionInfo(session),
+	}, nil
+}
+
+func (s *RealService) GetJwks(ctx context.Context, request *GetJwksRequest) (*GetJwksResponse, error) {
+	return &GetJwksResponse{s.jwtManager.PublicKeys()}, nil
+}
+
+// authenticate rejects tokens restricted to the password change. The cut-off of the user is checked by the caller in
+// its own unit of work, see checkTokensValidAfter.
+func (s *RealService) authenticate(accessToken string) (*value_objects.AuthInfo, error) {
+	authInfo, err := s.parseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo.Scope != "" {
+		return nil, &services.InvariantViolationError{Message: "access token only allows to change the password"}
+	}
+
+	return authInfo, nil
+}
+
+// parseAccessToken also accepts tokens restricted to the password change.
+func (s *RealService) parseAccessToken(accessToken string) (*value_objects.AuthInfo, error) {
+	authInfo, err := s.jwtManager.Parse(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.isRevoked(authInfo) {
+		return nil, &services.TokenError{Reason: services.TokenRevoked, Message: "token is revoked"}
+	}
+
+	return authInfo, nil
+}
+
+// checkTokensValidAfter rejects tokens the user has invalidated by RevokeAllAccessTokens or a password change. It runs
+// in the unit of work of the operation, which keeps the cut-off from changing until the operation is saved. RPCs that
+// load the user anyway check its row with checkIssuedAfter instead.
+func (s *RealService) checkTokensValidAfter(ctx context.Context, userRepository services.UserRepository, authInfo *value_objects.AuthInfo) error {
+	tokensValidAfter, exists, err := userRepository.TryGetTokensValidAfter(ctx, authInfo.UserUuid)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return &services.InvariantViolationError{Message: "user not found"}
+	}
+
+	return checkIssuedAfter(authInfo, tokensValidAfter)
+}
+
+// isRevoked is false for tokens without an id, they cannot be revoked.
+func (s *RealService) isRevoked(authInfo *value_objects.AuthInfo) bool {
+	return authInfo.TokenUuid != uuid.Nil && s.revokedTokenCache.Contains(authInfo.TokenUuid)
+}
+
+func (s *RealService) verifyPassword(user *entities.User, password string) bool {
+	var saltedPassword string
+	if user.Salt == "" {
+		saltedPassword = s.salter.LegacySalt(user.Uuid, user.CreatedAt, user.Name, password)
+	} else {
+		saltedPassword = s.salter.Salt(user.Salt, password)
+	}
+
+	return s.verifySaltedPassword(saltedPassword, user.PepperId, user.Password)
+}
+
+func (s *RealService) verifySaltedPassword(saltedPassword, pepperId, hash string) bool {
+	pepperedPassword, ok := s.pepperer.Pepper(pepperId, saltedPassword)
+	if !ok {
+		return false
+	}
+
+	return s.hasher.Verify(pepperedPassword, hash)
+}
+
+func (s *RealService) verifyDummyPassword(password string) {
+	s.dummyPassword.once.Do(func() {
+		s.dummyPassword.hash = s.hasher.Hash("")
+	})
+
+	_ = s.hasher.Verify(password, s.dummyPassword.hash)
+}
+
+func (s *RealService) isPasswordReused(user *entities.User, history []*entities.PasswordHistoryEntry, password string) bool {
+	if s.verifyPassword(user, password) {
+		return true
+	}
+
+	for _, entry := range history {
+		if s.verifySaltedPassword(s.salter.Salt(entry.Salt, password), entry.PepperId, entry.Password) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hashPassword hashes the password with a fresh salt and the active pepper.
+func (s *RealService) hashPassword(password string) (string, string, string) {
+	salt := s.salter.Generate()
+	pepperId := s.pepperer.ActiveId()
+
+	// The active pepper is always known to the pepperer
+	pepperedPassword, _ := s.pepperer.Pepper(pepperId, s.salter.Salt(salt, password))
+
+	return s.hasher.Hash(pepperedPassword), salt, pepperId
+}
+
+// setPassword gives the user a fresh salt and replaces the stored hash using the active pepper.
+func (s *RealService) setPassword(user *entities.User, password string) {
+	user.Password, user.Salt, user.PepperId = s.hashPassword(password)
+}
+
+func (s *RealService) checkName(name string) error {
+	violations := s.usernamePolicy.Check(name)
+	if len(violations) > 0 {
+		return &services.PolicyError{Field: "username", Violations: violations}
+	}
+
+	return nil
+}
+
+// checkPassword collects the policy violations of a new password, a breached password is reported as one more violation.
+func (s *RealService) checkPassword(name, password string) error {
+	violations := s.passwordPolicy.Check(name, password)
+
+	breached, err := s.breachedPasswordChecker.IsBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_BREACHED",
+			Description: "password appears in known data breaches",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &services.PolicyError{Field: "password", Violations: violations}
+	}
+
+	return nil
+}
+
+func (s *RealService) passwordIsExpired(user *entities.User, now time.Time) bool {
+	return s.maxPasswordAge > 0 && now.After(user.PasswordChangedAt.Add(s.maxPasswordAge))
+}
+
+func (s *RealService) passwordNeedsUpgrade(user *entities.User) bool {
+	return user.Salt == "" || user.PepperId != s.pepperer.ActiveId() || s.hasher.NeedsRehash(user.Password)
+}
+
+// parseKeptRefreshToken returns uuid.Nil for an empty token. It matches no session, so all of them are revoked
+// when the caller has not passed its own.
+func parseKeptRefreshToken(refreshToken string) (uuid.UUID, error) {
+	if refreshToken == "" {
+		return uuid.Nil, nil
+	}
+
+	keptRefreshToken, err := uuid.Parse(refreshToken)
+	if err != nil {
+		return uuid.Nil, &services.InvariantViolationError{Message: "refresh token format is invalid"}
+	}
+
+	return keptRefreshToken, nil
+}
+
+// checkIssuedAfter compares at the full precision of the cut-off. As iat is in whole seconds, tokens issued in the
+// same second as the invalidation are rejected too, even the ones issued right after it.
+func checkIssuedAfter(authInfo *value_objects.AuthInfo, tokensValidAfter time.Time) error {
+	if authInfo.IssuedAt.Before(tokensValidAfter) {
+		return &services.TokenError{Reason: services.TokenRevoked, Message: "token is revoked"}
+	}
+
+	return nil
+}
+
+// newSessionInfo returns nil for a nil session.
+func newSessionInfo(session *entities.Session) *SessionInfo {
+	if session == nil {
+		return nil
+	}
+
+	return &SessionInfo{
+		Id:           session.Uuid.String(),
+		CreatedAt:    session.CreatedAt,
+		RefreshedAt:  session.RefreshedAt,
+		ExpirationAt: session.ExpirationAt,
+		ClientIp:     session.ClientIp,
+		UserAgent:    session.UserAgent,
+	}
 }
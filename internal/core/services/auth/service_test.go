@@ -4,7 +4,9 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"grpc-auth/internal/core/entities"
+	"grpc-auth/internal/core/services"
 	"grpc-auth/internal/core/services/auth"
 	"grpc-auth/internal/core/value-objects"
 	"grpc-auth/internal/infrastructure"
@@ -16,6 +18,10 @@ func TestRegister(t *testing.T) {
 	// Arrange
 	const accessTokenLifetime time.Duration = 0
 	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
 	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
 	unitOfWork := infrastructure.NewMockUnitOfWork()
 	userRepository := infrastructure.NewMockUserRepository()
@@ -23,7 +29,13 @@ func TestRegister(t *testing.T) {
 	uuidProvider := infrastructure.NewMockUuidProvider()
 	hasher := infrastructure.NewMockHasher()
 	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
 	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
 
 	password := "password"
 	saltedPassword := password + "salt"
@@ -31,7 +43,8 @@ func TestRegister(t *testing.T) {
 	userCreatedAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
 	userName := "Name"
 	userPassword := saltedPassword + "hash"
-	user := entities.NewUser(userUuid, userCreatedAt, userName, userPassword)
+	userSalt := "salt"
+	user := entities.NewUser(userUuid, userCreatedAt, userName, "name", userPassword, userSalt, "pepper", userCreatedAt, time.Time{})
 	ctx := context.TODO()
 
 	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
@@ -41,10 +54,17 @@ func TestRegister(t *testing.T) {
 	timeProvider.On("Now").Return(userCreatedAt)
 	uuidProvider.On("Random").Return(userUuid)
 	hasher.On("Hash", saltedPassword).Return(userPassword)
-	salter.On("Salt", userUuid, userCreatedAt, userName, password).Return(saltedPassword)
+	salter.On("Generate").Return(userSalt)
+	salter.On("Salt", userSalt, password).Return(saltedPassword)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
+	usernamePolicy.On("Check", userName).Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", userName).Return("name")
+	passwordPolicy.On("Check", userName, password).Return([]value_objects.Violation(nil))
+	breachedPasswordChecker.On("IsBreached", password).Return(false, nil)
 
 	request := &auth.RegisterRequest{Name: userName, Password: password}
-	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	// Act
 	response, err := service.Register(ctx, request)
@@ -57,16 +77,157 @@ func TestRegister(t *testing.T) {
 	unitOfWork.AssertCalled(t, "UserRepository")
 	uuidProvider.AssertCalled(t, "Random")
 	timeProvider.AssertCalled(t, "Now")
-	salter.AssertCalled(t, "Salt", userUuid, userCreatedAt, userName, password)
+	salter.AssertCalled(t, "Generate")
+	salter.AssertCalled(t, "Salt", userSalt, password)
 	hasher.AssertCalled(t, "Hash", saltedPassword)
 	userRepository.AssertCalled(t, "TryCreate", ctx, user)
 	unitOfWork.AssertCalled(t, "Save", ctx)
 }
 
+func Test_Register_NameViolatesPolicy(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userName := "Admin"
+	violations := []value_objects.Violation{{Reason: "USERNAME_RESERVED", Description: "username is reserved"}}
+	expectedErr := &services.PolicyError{Field: "username", Violations: violations}
+	ctx := context.TODO()
+
+	usernamePolicy.On("Check", userName).Return(violations)
+
+	request := &auth.RegisterRequest{Name: userName, Password: "password"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.Register(ctx, request)
+	t.Log(response)
+	t.Log(err)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, expectedErr, err)
+	passwordPolicy.AssertNotCalled(t, "Check", userName, "password")
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_Register_PasswordViolatesPolicy(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userName := "Name"
+	violations := []value_objects.Violation{{Reason: "PASSWORD_TOO_SHORT", Description: "password is too short"}}
+	expectedErr := &services.PolicyError{Field: "password", Violations: violations}
+	ctx := context.TODO()
+
+	usernamePolicy.On("Check", userName).Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", userName).Return("name")
+	passwordPolicy.On("Check", userName, "").Return(violations)
+	breachedPasswordChecker.On("IsBreached", "").Return(false, nil)
+
+	request := &auth.RegisterRequest{Name: userName, Password: ""}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.Register(ctx, request)
+	t.Log(response)
+	t.Log(err)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, expectedErr, err)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_Register_PasswordIsBreached(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userName := "Name"
+	password := "P@ssw0rd123"
+	expectedErr := &services.PolicyError{Field: "password", Violations: []value_objects.Violation{{
+		Reason:      "PASSWORD_BREACHED",
+		Description: "password appears in known data breaches",
+	}}}
+	ctx := context.TODO()
+
+	usernamePolicy.On("Check", userName).Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", userName).Return("name")
+	passwordPolicy.On("Check", userName, password).Return([]value_objects.Violation(nil))
+	breachedPasswordChecker.On("IsBreached", password).Return(true, nil)
+
+	request := &auth.RegisterRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.Register(ctx, request)
+	t.Log(response)
+	t.Log(err)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.Equal(t, expectedErr, err)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
 func TestLogin(t *testing.T) {
 	// Arrange
 	const accessTokenLifetime time.Duration = 0
 	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
 	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
 	unitOfWork := infrastructure.NewMockUnitOfWork()
 	userRepository := infrastructure.NewMockUserRepository()
@@ -75,7 +236,13 @@ func TestLogin(t *testing.T) {
 	uuidProvider := infrastructure.NewMockUuidProvider()
 	hasher := infrastructure.NewMockHasher()
 	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
 	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
 
 	password := "password"
 	saltedPassword := password + "salt"
@@ -83,9 +250,11 @@ func TestLogin(t *testing.T) {
 	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
 	userName := "Name"
 	userPassword := saltedPassword + "hash"
-	user := entities.NewUser(fakeUuid, fakeNow, userName, userPassword)
-	session := entities.NewSession(fakeUuid, fakeUuid, fakeNow)
-	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", userPassword, "salt", "pepper", fakeNow, time.Time{})
+	clientIp := "127.0.0.1"
+	userAgent := "grpc-go/1.71.1"
+	session := entities.NewSession(fakeUuid, fakeUuid, fakeNow, fakeUuid, fakeNow, fakeNow, clientIp, userAgent)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow, TokenUuid: fakeUuid, SessionUuid: fakeUuid}
 	accessToken := "Fake access token"
 	ctx := context.TODO()
 
@@ -93,16 +262,20 @@ func TestLogin(t *testing.T) {
 	unitOfWork.On("UserRepository").Return(userRepository)
 	unitOfWork.On("SessionRepository").Return(sessionRepository)
 	unitOfWork.On("Save", ctx).Return(nil)
-	userRepository.On("TryGetByName", ctx, userName).Return(user, nil)
+	userRepository.On("TryGetByName", ctx, "name").Return(user, nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
 	sessionRepository.On("Create", ctx, session).Return(nil)
 	timeProvider.On("Now").Return(fakeNow)
 	uuidProvider.On("Random").Return(fakeUuid)
-	hasher.On("Hash", saltedPassword).Return(userPassword)
-	salter.On("Salt", fakeUuid, fakeNow, userName, password).Return(saltedPassword)
+	hasher.On("Verify", saltedPassword, userPassword).Return(true)
+	hasher.On("NeedsRehash", userPassword).Return(false)
+	salter.On("Salt", "salt", password).Return(saltedPassword)
 	jwtManager.On("Generate", authInfo).Return(accessToken, nil)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
 
-	request := &auth.LoginRequest{Name: userName, Password: password}
-	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
+	request := &auth.LoginRequest{Name: userName, Password: password, ClientIp: clientIp, UserAgent: userAgent}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	// Act
 	response, err := service.Login(ctx, request)
@@ -114,9 +287,10 @@ func TestLogin(t *testing.T) {
 	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
 	unitOfWork.AssertCalled(t, "UserRepository")
 	unitOfWork.AssertCalled(t, "SessionRepository")
-	userRepository.AssertCalled(t, "TryGetByName", ctx, userName)
-	salter.AssertCalled(t, "Salt", fakeUuid, fakeNow, userName, password)
-	hasher.AssertCalled(t, "Hash", saltedPassword)
+	userRepository.AssertCalled(t, "TryGetByName", ctx, "name")
+	salter.AssertCalled(t, "Salt", "salt", password)
+	hasher.AssertCalled(t, "Verify", saltedPassword, userPassword)
+	hasher.AssertNotCalled(t, "Hash", saltedPassword)
 	timeProvider.AssertCalled(t, "Now")
 	jwtManager.AssertCalled(t, "Generate", authInfo)
 	uuidProvider.AssertCalled(t, "Random")
@@ -124,122 +298,380 @@ func TestLogin(t *testing.T) {
 	unitOfWork.AssertCalled(t, "Save", ctx)
 }
 
-func Test_CheckAccessToken_ExpirationAtIsInvalid(t *testing.T) {
+func Test_Login_UserDoesNotExist(t *testing.T) {
 	// Arrange
 	const accessTokenLifetime time.Duration = 0
 	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
 	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "password"
+	userName := "Name"
+	dummyHash := "dummy hash"
+	ctx := context.TODO()
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByName", ctx, "name").Return((*entities.User)(nil), nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
+	hasher.On("Hash", "").Return(dummyHash)
+	hasher.On("Verify", password, dummyHash).Return(false)
+
+	request := &auth.LoginRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	_, err := service.Login(ctx, request)
+	_, repeatedErr := service.Login(ctx, request)
+
+	// Assert
+	assert.EqualError(t, err, "login or/and password is invalid")
+	assert.EqualError(t, repeatedErr, "login or/and password is invalid")
+	hasher.AssertNumberOfCalls(t, "Hash", 1)
+	hasher.AssertNumberOfCalls(t, "Verify", 2)
+	hasher.AssertCalled(t, "Verify", password, dummyHash)
+	jwtManager.AssertNotCalled(t, "Generate", mock.Anything)
+	unitOfWork.AssertNotCalled(t, "Save", ctx)
+}
 
+func Test_Login_PasswordIsExpired(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime = 10 * time.Minute
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
 	timeProvider := infrastructure.NewMockTimeProvider()
 	uuidProvider := infrastructure.NewMockUuidProvider()
 	hasher := infrastructure.NewMockHasher()
 	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
 	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
 
+	password := "password"
+	saltedPassword := password + "salt"
 	fakeUuid := uuid.Nil
-	older := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
-	newer := time.Date(2025, 4, 8, 14, 39, 1, 0, time.UTC)
-	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: older}
-	accessToken := "Fake access token"
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	passwordChangedAt := fakeNow.Add(-maxPasswordAge - time.Second)
+	userName := "Name"
+	userPassword := saltedPassword + "hash"
+	user := entities.NewUser(fakeUuid, passwordChangedAt, userName, "name", userPassword, "salt", "pepper", passwordChangedAt, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow.Add(passwordChangeTokenLifetime), Scope: value_objects.PasswordChangeScope, TokenUuid: fakeUuid}
+	accessToken := "Fake restricted access token"
+	expectedResponse := &auth.LoginResponse{AccessToken: accessToken, PasswordExpired: true}
 	ctx := context.TODO()
 
-	timeProvider.On("Now").Return(newer)
-	jwtManager.On("Parse", accessToken).Return(authInfo)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByName", ctx, "name").Return(user, nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
+	timeProvider.On("Now").Return(fakeNow)
+	uuidProvider.On("Random").Return(fakeUuid)
+	hasher.On("Verify", saltedPassword, userPassword).Return(true)
+	hasher.On("NeedsRehash", userPassword).Return(false)
+	salter.On("Salt", "salt", password).Return(saltedPassword)
+	jwtManager.On("Generate", authInfo).Return(accessToken, nil)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
 
-	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
-	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
-	expectedResponse := auth.CheckAccessTokenResponse{IsActive: false}
+	request := &auth.LoginRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	// Act
-	actualResponse, err := service.CheckAccessToken(ctx, request)
+	actualResponse, err := service.Login(ctx, request)
 	t.Log(actualResponse)
-	t.Log(err)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResponse, *actualResponse)
-	jwtManager.AssertCalled(t, "Parse", accessToken)
-	timeProvider.AssertCalled(t, "Now")
+	assert.Equal(t, expectedResponse, actualResponse)
+	jwtManager.AssertCalled(t, "Generate", authInfo)
+	sessionRepository.AssertNotCalled(t, "Create", ctx, mock.Anything)
+	unitOfWork.AssertCalled(t, "Save", ctx)
 }
 
-func Test_CheckAccessToken_UserUuidIsInvalid(t *testing.T) {
+func Test_Login_RehashesOutdatedPassword(t *testing.T) {
 	// Arrange
 	const accessTokenLifetime time.Duration = 0
 	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
 	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
 	unitOfWork := infrastructure.NewMockUnitOfWork()
 	userRepository := infrastructure.NewMockUserRepository()
-
+	sessionRepository := infrastructure.NewMockSessionRepository()
 	timeProvider := infrastructure.NewMockTimeProvider()
 	uuidProvider := infrastructure.NewMockUuidProvider()
 	hasher := infrastructure.NewMockHasher()
 	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
 	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
 
+	password := "password"
+	oldSaltedPassword := password + "old salt"
+	newSaltedPassword := password + "new salt"
 	fakeUuid := uuid.Nil
-	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
 	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
-	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt}
-	accessToken := "Fake access token"
+	userName := "Name"
+	outdatedPassword := oldSaltedPassword + "outdated hash"
+	currentPassword := newSaltedPassword + "current hash"
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", outdatedPassword, "old salt", "pepper", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, userName, "name", currentPassword, "new salt", "pepper", fakeNow, time.Time{})
+	session := entities.NewSession(fakeUuid, fakeUuid, fakeNow, fakeUuid, fakeNow, fakeNow, "", "")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
 	ctx := context.TODO()
 
-	timeProvider.On("Now").Return(fakeNow)
-	jwtManager.On("Parse", accessToken).Return(authInfo)
 	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
 	unitOfWork.On("UserRepository").Return(userRepository)
-	unitOfWork.On("Rollback", ctx).Return(nil)
-	userRepository.On("Exists", ctx, fakeUuid).Return(false, nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByName", ctx, "name").Return(user, nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	sessionRepository.On("Create", ctx, session).Return(nil)
+	timeProvider.On("Now").Return(fakeNow)
+	uuidProvider.On("Random").Return(fakeUuid)
+	hasher.On("Verify", oldSaltedPassword, outdatedPassword).Return(true)
+	hasher.On("NeedsRehash", outdatedPassword).Return(true)
+	hasher.On("Hash", newSaltedPassword).Return(currentPassword)
+	salter.On("Salt", "old salt", password).Return(oldSaltedPassword)
+	salter.On("Generate").Return("new salt")
+	salter.On("Salt", "new salt", password).Return(newSaltedPassword)
+	jwtManager.On("Generate", authInfo).Return("Fake access token", nil)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "pepper", oldSaltedPassword).Return(oldSaltedPassword, true)
+	pepperer.On("Pepper", "pepper", newSaltedPassword).Return(newSaltedPassword, true)
 
-	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
-	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
+	request := &auth.LoginRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	// Act
-	actualResponse, err := service.CheckAccessToken(ctx, request)
-	t.Log(actualResponse)
-	t.Log(err)
+	response, err := service.Login(ctx, request)
+	t.Log(response)
 
 	// Assert
-	assert.Error(t, err)
-	assert.Empty(t, actualResponse)
-	jwtManager.AssertCalled(t, "Parse", accessToken)
-	timeProvider.AssertCalled(t, "Now")
-	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
-	unitOfWork.AssertCalled(t, "UserRepository")
-	userRepository.AssertCalled(t, "Exists", ctx, fakeUuid)
-	unitOfWork.AssertCalled(t, "Rollback", ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response)
+	hasher.AssertCalled(t, "Verify", oldSaltedPassword, outdatedPassword)
+	hasher.AssertCalled(t, "Hash", newSaltedPassword)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
 }
 
-func Test_CheckAccessToken_IsValid(t *testing.T) {
+func Test_Login_ResaltsLegacyPassword(t *testing.T) {
 	// Arrange
 	const accessTokenLifetime time.Duration = 0
 	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
 	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
 	unitOfWork := infrastructure.NewMockUnitOfWork()
 	userRepository := infrastructure.NewMockUserRepository()
-
+	sessionRepository := infrastructure.NewMockSessionRepository()
 	timeProvider := infrastructure.NewMockTimeProvider()
 	uuidProvider := infrastructure.NewMockUuidProvider()
 	hasher := infrastructure.NewMockHasher()
 	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
 	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
 
+	password := "password"
+	legacySaltedPassword := password + "legacy salt"
+	newSaltedPassword := password + "new salt"
 	fakeUuid := uuid.Nil
-	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
 	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
-	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt}
-	accessToken := "Fake access token"
+	userName := "Name"
+	legacyPassword := legacySaltedPassword + "hash"
+	currentPassword := newSaltedPassword + "hash"
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", legacyPassword, "", "", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, userName, "name", currentPassword, "new salt", "pepper", fakeNow, time.Time{})
+	session := entities.NewSession(fakeUuid, fakeUuid, fakeNow, fakeUuid, fakeNow, fakeNow, "", "")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
 	ctx := context.TODO()
 
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByName", ctx, "name").Return(user, nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	sessionRepository.On("Create", ctx, session).Return(nil)
 	timeProvider.On("Now").Return(fakeNow)
-	jwtManager.On("Parse", accessToken).Return(authInfo)
+	uuidProvider.On("Random").Return(fakeUuid)
+	hasher.On("Verify", legacySaltedPassword, legacyPassword).Return(true)
+	hasher.On("Hash", newSaltedPassword).Return(currentPassword)
+	salter.On("LegacySalt", fakeUuid, fakeNow, userName, password).Return(legacySaltedPassword)
+	salter.On("Generate").Return("new salt")
+	salter.On("Salt", "new salt", password).Return(newSaltedPassword)
+	jwtManager.On("Generate", authInfo).Return("Fake access token", nil)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "", legacySaltedPassword).Return(legacySaltedPassword, true)
+	pepperer.On("Pepper", "pepper", newSaltedPassword).Return(newSaltedPassword, true)
+
+	request := &auth.LoginRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.Login(ctx, request)
+	t.Log(response)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response)
+	salter.AssertCalled(t, "LegacySalt", fakeUuid, fakeNow, userName, password)
+	hasher.AssertCalled(t, "Verify", legacySaltedPassword, legacyPassword)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_Login_RepeppersWithActivePepper(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "password"
+	oldSaltedPassword := password + "old salt"
+	newSaltedPassword := password + "new salt"
+	oldPepperedPassword := oldSaltedPassword + "old pepper"
+	newPepperedPassword := newSaltedPassword + "pepper"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	userName := "Name"
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", oldPepperedPassword+"hash", "old salt", "old pepper", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, userName, "name", newPepperedPassword+"hash", "new salt", "pepper", fakeNow, time.Time{})
+	session := entities.NewSession(fakeUuid, fakeUuid, fakeNow, fakeUuid, fakeNow, fakeNow, "", "")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	ctx := context.TODO()
+
 	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
 	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
 	unitOfWork.On("Save", ctx).Return(nil)
-	userRepository.On("Exists", ctx, fakeUuid).Return(true, nil)
+	userRepository.On("TryGetByName", ctx, "name").Return(user, nil)
+	usernamePolicy.On("Normalize", userName).Return("name")
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	sessionRepository.On("Create", ctx, session).Return(nil)
+	timeProvider.On("Now").Return(fakeNow)
+	uuidProvider.On("Random").Return(fakeUuid)
+	salter.On("Salt", "old salt", password).Return(oldSaltedPassword)
+	salter.On("Generate").Return("new salt")
+	salter.On("Salt", "new salt", password).Return(newSaltedPassword)
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "old pepper", oldSaltedPassword).Return(oldPepperedPassword, true)
+	pepperer.On("Pepper", "pepper", newSaltedPassword).Return(newPepperedPassword, true)
+	hasher.On("Verify", oldPepperedPassword, oldPepperedPassword+"hash").Return(true)
+	hasher.On("Hash", newPepperedPassword).Return(newPepperedPassword + "hash")
+	jwtManager.On("Generate", authInfo).Return("Fake access token", nil)
+
+	request := &auth.LoginRequest{Name: userName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.Login(ctx, request)
+	t.Log(response)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response)
+	pepperer.AssertCalled(t, "Pepper", "old pepper", oldSaltedPassword)
+	pepperer.AssertCalled(t, "Pepper", "pepper", newSaltedPassword)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_CheckAccessToken_AccessTokenIsExpired(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	jwtManager.On("Parse", accessToken).Return((*value_objects.AuthInfo)(nil), &services.TokenError{Reason: services.TokenExpired, Message: "token is expired"})
 
 	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
-	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, jwtManager)
-	expectedResponse := auth.CheckAccessTokenResponse{IsActive: true}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
 
 	// Act
 	actualResponse, err := service.CheckAccessToken(ctx, request)
@@ -248,11 +680,2294 @@ func Test_CheckAccessToken_IsValid(t *testing.T) {
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResponse, *actualResponse)
+	assert.Equal(t, auth.CheckAccessTokenResponse{IsActive: false}, *actualResponse)
 	jwtManager.AssertCalled(t, "Parse", accessToken)
-	timeProvider.AssertCalled(t, "Now")
-	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
-	unitOfWork.AssertCalled(t, "UserRepository")
-	userRepository.AssertCalled(t, "Exists", ctx, fakeUuid)
-	unitOfWork.AssertCalled(t, "Save", ctx)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_CheckAccessToken_SignatureIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	tokenError := &services.TokenError{Reason: services.TokenSignatureInvalid, Message: "token signature is invalid"}
+	jwtManager.On("Parse", accessToken).Return((*value_objects.AuthInfo)(nil), tokenError)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Equal(t, tokenError, err)
+	assert.Empty(t, actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_CheckAccessToken_UserUuidIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, false, nil)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Empty(t, actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
+	unitOfWork.AssertCalled(t, "UserRepository")
+	userRepository.AssertCalled(t, "TryGetTokensValidAfter", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_CheckAccessToken_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.CheckAccessTokenResponse{IsActive: true}
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
+	unitOfWork.AssertCalled(t, "UserRepository")
+	userRepository.AssertCalled(t, "TryGetTokensValidAfter", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_CheckAccessToken_TokenIsIssuedBeforeInvalidation(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	tokensValidAfter := fakeNow.Add(-time.Minute)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt, IssuedAt: tokensValidAfter.Add(-time.Second)}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(tokensValidAfter, true, nil)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.CheckAccessTokenResponse{IsActive: false}
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
+	unitOfWork.AssertCalled(t, "UserRepository")
+	userRepository.AssertCalled(t, "TryGetTokensValidAfter", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_CheckAccessToken_TokenIsIssuedInTheSameSecondAfterInvalidation(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 1, 0, time.UTC)
+	// A login 300ms after the invalidation gets the iat of the invalidation second, so it is rejected as well
+	tokensValidAfter := time.Date(2025, 4, 8, 14, 39, 0, 700_000_000, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow.Add(time.Hour), IssuedAt: tokensValidAfter.Truncate(time.Second)}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(tokensValidAfter, true, nil)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.CheckAccessTokenResponse{IsActive: false}
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	userRepository.AssertCalled(t, "TryGetTokensValidAfter", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_CheckAccessToken_SessionIsDeleted(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = true
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	sessionUuid := uuid.MustParse("7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d")
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt, SessionUuid: sessionUuid}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+	sessionRepository.On("ExistsByUuid", ctx, sessionUuid).Return(false, nil)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.CheckAccessTokenResponse{IsActive: false}
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
+	unitOfWork.AssertCalled(t, "UserRepository")
+	userRepository.AssertCalled(t, "TryGetTokensValidAfter", ctx, fakeUuid)
+	sessionRepository.AssertCalled(t, "ExistsByUuid", ctx, sessionUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_DeleteSession_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	refreshToken, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	fakeUuid := uuid.Nil
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	session := entities.NewSession(refreshToken, fakeUuid, fakeExpirationAt, fakeUuid, fakeExpirationAt, fakeExpirationAt, "", "")
+	ctx := context.TODO()
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return(session, nil)
+	sessionRepository.On("DeleteByRefreshToken", ctx, refreshToken).Return(nil)
+
+	request := &auth.DeleteSessionRequest{RefreshToken: refreshToken.String()}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteSession(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	unitOfWorkStarter.AssertCalled(t, "Start", ctx)
+	unitOfWork.AssertCalled(t, "SessionRepository")
+	sessionRepository.AssertCalled(t, "TryGetByRefreshToken", ctx, refreshToken)
+	sessionRepository.AssertCalled(t, "DeleteByRefreshToken", ctx, refreshToken)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_DeleteSession_RefreshTokenDoesNotExist(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	refreshToken, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	ctx := context.TODO()
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return((*entities.Session)(nil), nil)
+
+	request := &auth.DeleteSessionRequest{RefreshToken: refreshToken.String()}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteSession(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "refresh token does not exists")
+	assert.Empty(t, actualResponse)
+	sessionRepository.AssertCalled(t, "TryGetByRefreshToken", ctx, refreshToken)
+	sessionRepository.AssertNotCalled(t, "DeleteByRefreshToken", ctx, refreshToken)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_DeleteSession_RefreshTokenFormatIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	ctx := context.TODO()
+
+	request := &auth.DeleteSessionRequest{RefreshToken: "Fake refresh token"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteSession(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "refresh token format is invalid")
+	assert.Empty(t, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_ChangeLogin_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "password"
+	saltedPassword := password + "salt"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, fakeNow, "Name", "name", saltedPassword+"hash", "salt", "pepper", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, "NewName", "newname", saltedPassword+"hash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	salter.On("Salt", "salt", password).Return(saltedPassword)
+	hasher.On("Verify", saltedPassword, saltedPassword+"hash").Return(true)
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
+	usernamePolicy.On("Check", "NewName").Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", "NewName").Return("newname")
+
+	request := &auth.ChangeLoginRequest{AccessToken: accessToken, NewName: "NewName", Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangeLogin(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	userRepository.AssertCalled(t, "TryGetByUuid", ctx, fakeUuid)
+	hasher.AssertCalled(t, "Verify", saltedPassword, saltedPassword+"hash")
+	salter.AssertNotCalled(t, "Generate")
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_ChangeLogin_ResaltsLegacyPassword(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "password"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	oldName := "Name"
+	newName := "NewName"
+	legacySaltedPassword := password + "legacy salt"
+	newSaltedPassword := password + "new salt"
+	user := entities.NewUser(fakeUuid, fakeNow, oldName, "name", legacySaltedPassword+"hash", "", "", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, newName, "newname", newSaltedPassword+"hash", "new salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	salter.On("LegacySalt", fakeUuid, fakeNow, oldName, password).Return(legacySaltedPassword)
+	salter.On("Generate").Return("new salt")
+	salter.On("Salt", "new salt", password).Return(newSaltedPassword)
+	hasher.On("Verify", legacySaltedPassword, legacySaltedPassword+"hash").Return(true)
+	hasher.On("Hash", newSaltedPassword).Return(newSaltedPassword + "hash")
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "", legacySaltedPassword).Return(legacySaltedPassword, true)
+	pepperer.On("Pepper", "pepper", newSaltedPassword).Return(newSaltedPassword, true)
+	usernamePolicy.On("Check", newName).Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", newName).Return("newname")
+
+	request := &auth.ChangeLoginRequest{AccessToken: accessToken, NewName: newName, Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangeLogin(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	salter.AssertCalled(t, "LegacySalt", fakeUuid, fakeNow, oldName, password)
+	salter.AssertCalled(t, "Generate")
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_ChangeLogin_NameIsTaken(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "password"
+	saltedPassword := password + "salt"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, fakeNow, "Name", "name", saltedPassword+"hash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, user).Return(false, nil)
+	salter.On("Salt", "salt", password).Return(saltedPassword)
+	hasher.On("Verify", saltedPassword, saltedPassword+"hash").Return(true)
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
+	usernamePolicy.On("Check", "TakenName").Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", "TakenName").Return("takenname")
+
+	request := &auth.ChangeLoginRequest{AccessToken: accessToken, NewName: "TakenName", Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangeLogin(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "login is already taken")
+	assert.Empty(t, actualResponse)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, user)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+	unitOfWork.AssertNotCalled(t, "Save", ctx)
+}
+
+func Test_ChangeLogin_PasswordIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	password := "wrong password"
+	saltedPassword := password + "salt"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, fakeNow, "Name", "name", "password hash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	salter.On("Salt", "salt", password).Return(saltedPassword)
+	hasher.On("Verify", saltedPassword, "password hash").Return(false)
+	pepperer.On("Pepper", "pepper", saltedPassword).Return(saltedPassword, true)
+	usernamePolicy.On("Check", "NewName").Return([]value_objects.Violation(nil))
+	usernamePolicy.On("Normalize", "NewName").Return("newname")
+
+	request := &auth.ChangeLoginRequest{AccessToken: accessToken, NewName: "NewName", Password: password}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangeLogin(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "password is invalid")
+	assert.Empty(t, actualResponse)
+	userRepository.AssertNotCalled(t, "TryUpdate", ctx, user)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_ChangePassword_RevokesOtherSessions(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	passwordHistoryRepository := infrastructure.NewMockPasswordHistoryRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	currentPassword := "password"
+	newPassword := "new password"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	userName := "Name"
+	refreshToken, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", currentPassword+"salthash", "salt", "pepper", fakeNow, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, fakeNow, userName, "name", newPassword+"salthash", "new salt", "pepper", fakeNow, fakeNow)
+	historyEntry := entities.NewPasswordHistoryEntry(fakeUuid, fakeUuid, fakeNow, "old passwordold salthash", "old salt", "pepper")
+	expectedHistoryEntry := entities.NewPasswordHistoryEntry(fakeUuid, fakeUuid, fakeNow, currentPassword+"salthash", "new salt", "pepper")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("PasswordHistoryRepository").Return(passwordHistoryRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+	sessionRepository.On("DeleteByUserUuid", ctx, fakeUuid, refreshToken).Return(nil)
+	passwordHistoryRepository.On("GetLatestByUserUuid", ctx, fakeUuid, passwordHistoryDepth-1).Return([]*entities.PasswordHistoryEntry{historyEntry}, nil)
+	passwordHistoryRepository.On("Create", ctx, expectedHistoryEntry).Return(nil)
+	passwordHistoryRepository.On("DeleteAllButLatestByUserUuid", ctx, fakeUuid, passwordHistoryDepth-1).Return(nil)
+	uuidProvider.On("Random").Return(fakeUuid)
+	salter.On("Salt", "salt", currentPassword).Return(currentPassword + "salt")
+	salter.On("Salt", "salt", newPassword).Return(newPassword + "salt")
+	salter.On("Salt", "old salt", newPassword).Return(newPassword + "old salt")
+	salter.On("Generate").Return("new salt")
+	salter.On("Salt", "new salt", currentPassword).Return(currentPassword + "salt")
+	salter.On("Salt", "new salt", newPassword).Return(newPassword + "salt")
+	hasher.On("Verify", currentPassword+"salt", currentPassword+"salthash").Return(true)
+	hasher.On("Verify", newPassword+"salt", currentPassword+"salthash").Return(false)
+	hasher.On("Verify", newPassword+"old salt", "old passwordold salthash").Return(false)
+	hasher.On("Hash", currentPassword+"salt").Return(currentPassword + "salthash")
+	hasher.On("Hash", newPassword+"salt").Return(newPassword + "salthash")
+	pepperer.On("ActiveId").Return("pepper")
+	pepperer.On("Pepper", "pepper", currentPassword+"salt").Return(currentPassword+"salt", true)
+	pepperer.On("Pepper", "pepper", newPassword+"salt").Return(newPassword+"salt", true)
+	pepperer.On("Pepper", "pepper", newPassword+"old salt").Return(newPassword+"old salt", true)
+	passwordPolicy.On("Check", userName, newPassword).Return([]value_objects.Violation(nil))
+	breachedPasswordChecker.On("IsBreached", newPassword).Return(false, nil)
+
+	request := &auth.ChangePasswordRequest{
+		AccessToken:         accessToken,
+		CurrentPassword:     currentPassword,
+		NewPassword:         newPassword,
+		RevokeOtherSessions: true,
+		RefreshToken:        refreshToken.String(),
+	}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangePassword(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	userRepository.AssertCalled(t, "TryGetByUuid", ctx, fakeUuid)
+	hasher.AssertCalled(t, "Verify", currentPassword+"salt", currentPassword+"salthash")
+	hasher.AssertCalled(t, "Hash", newPassword+"salt")
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	passwordHistoryRepository.AssertCalled(t, "Create", ctx, expectedHistoryEntry)
+	passwordHistoryRepository.AssertCalled(t, "DeleteAllButLatestByUserUuid", ctx, fakeUuid, passwordHistoryDepth-1)
+	sessionRepository.AssertCalled(t, "DeleteByUserUuid", ctx, fakeUuid, refreshToken)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_ChangePassword_CurrentPasswordIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	passwordHistoryRepository := infrastructure.NewMockPasswordHistoryRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	currentPassword := "wrong password"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, fakeNow, "Name", "name", "password hash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("PasswordHistoryRepository").Return(passwordHistoryRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	salter.On("Salt", "salt", currentPassword).Return(currentPassword + "salt")
+	hasher.On("Verify", currentPassword+"salt", "password hash").Return(false)
+	pepperer.On("Pepper", "pepper", currentPassword+"salt").Return(currentPassword+"salt", true)
+
+	request := &auth.ChangePasswordRequest{AccessToken: accessToken, CurrentPassword: currentPassword, NewPassword: "new password", RevokeOtherSessions: true}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangePassword(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "password is invalid")
+	assert.Empty(t, actualResponse)
+	userRepository.AssertNotCalled(t, "TryUpdate", ctx, user)
+	sessionRepository.AssertNotCalled(t, "DeleteByUserUuid", ctx, fakeUuid, uuid.Nil)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_ChangePassword_NewPasswordViolatesPolicy(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	passwordHistoryRepository := infrastructure.NewMockPasswordHistoryRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	currentPassword := "password"
+	newPassword := "Name"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, fakeNow, "Name", "name", currentPassword+"salthash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	violations := []value_objects.Violation{{Reason: "PASSWORD_SIMILAR_TO_LOGIN", Description: "password is similar to login"}}
+	expectedErr := &services.PolicyError{Field: "password", Violations: violations}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("PasswordHistoryRepository").Return(passwordHistoryRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	salter.On("Salt", "salt", currentPassword).Return(currentPassword + "salt")
+	hasher.On("Verify", currentPassword+"salt", currentPassword+"salthash").Return(true)
+	pepperer.On("Pepper", "pepper", currentPassword+"salt").Return(currentPassword+"salt", true)
+	passwordPolicy.On("Check", "Name", newPassword).Return(violations)
+	breachedPasswordChecker.On("IsBreached", newPassword).Return(false, nil)
+
+	request := &auth.ChangePasswordRequest{AccessToken: accessToken, CurrentPassword: currentPassword, NewPassword: newPassword}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangePassword(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Empty(t, actualResponse)
+	userRepository.AssertNotCalled(t, "TryUpdate", ctx, user)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_ChangePassword_PasswordWasUsedRecently(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	passwordHistoryRepository := infrastructure.NewMockPasswordHistoryRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	currentPassword := "password"
+	newPassword := "old password"
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	userName := "Name"
+	user := entities.NewUser(fakeUuid, fakeNow, userName, "name", currentPassword+"salthash", "salt", "pepper", fakeNow, time.Time{})
+	historyEntry := entities.NewPasswordHistoryEntry(fakeUuid, fakeUuid, fakeNow, newPassword+"old salthash", "old salt", "old pepper")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	expectedErr := &services.PolicyError{Field: "password", Violations: []value_objects.Violation{{
+		Reason:      "PASSWORD_REUSED",
+		Description: "password must differ from the last 3 passwords",
+	}}}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("PasswordHistoryRepository").Return(passwordHistoryRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	passwordHistoryRepository.On("GetLatestByUserUuid", ctx, fakeUuid, passwordHistoryDepth-1).Return([]*entities.PasswordHistoryEntry{historyEntry}, nil)
+	salter.On("Salt", "salt", currentPassword).Return(currentPassword + "salt")
+	salter.On("Salt", "salt", newPassword).Return(newPassword + "salt")
+	salter.On("Salt", "old salt", newPassword).Return(newPassword + "old salt")
+	hasher.On("Verify", currentPassword+"salt", currentPassword+"salthash").Return(true)
+	hasher.On("Verify", newPassword+"salt", currentPassword+"salthash").Return(false)
+	hasher.On("Verify", newPassword+"old salt", newPassword+"old salthash").Return(true)
+	pepperer.On("Pepper", "pepper", currentPassword+"salt").Return(currentPassword+"salt", true)
+	pepperer.On("Pepper", "pepper", newPassword+"salt").Return(newPassword+"salt", true)
+	pepperer.On("Pepper", "old pepper", newPassword+"old salt").Return(newPassword+"old salt", true)
+	passwordPolicy.On("Check", userName, newPassword).Return([]value_objects.Violation(nil))
+	breachedPasswordChecker.On("IsBreached", newPassword).Return(false, nil)
+
+	request := &auth.ChangePasswordRequest{AccessToken: accessToken, CurrentPassword: currentPassword, NewPassword: newPassword}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ChangePassword(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	assert.Empty(t, actualResponse)
+	userRepository.AssertNotCalled(t, "TryUpdate", ctx, user)
+	passwordHistoryRepository.AssertNotCalled(t, "Create", ctx, historyEntry)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_RefreshTokens_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime = time.Hour
+	const refreshTokenLifetime = 720 * time.Hour
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid := uuid.MustParse("b5b1d9f4-1d0e-4c0b-9b1e-3f6f1e6c2a10")
+	sessionUuid := uuid.MustParse("7c1a2b3c-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+	refreshToken := uuid.MustParse("0f9e8d7c-6b5a-4938-a7b6-c5d4e3f2a1b0")
+	newRefreshToken := uuid.MustParse("1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d")
+	tokenUuid := uuid.MustParse("2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	createdAt := fakeNow.Add(-24 * time.Hour)
+	clientIp := "127.0.0.1"
+	userAgent := "grpc-go/1.71.1"
+	session := entities.NewSession(refreshToken, userUuid, fakeNow.Add(time.Hour), sessionUuid, createdAt, createdAt, clientIp, userAgent)
+	ctx := context.TODO()
+
+	user := entities.NewUser(userUuid, createdAt, "Name", "name", "hash", "salt", "pepper", createdAt, time.Time{})
+	newSession := entities.NewSession(newRefreshToken, userUuid, fakeNow.Add(refreshTokenLifetime), sessionUuid, createdAt, fakeNow, clientIp, userAgent)
+	authInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: fakeNow.Add(accessTokenLifetime), TokenUuid: tokenUuid, SessionUuid: sessionUuid}
+	accessToken := "Fake access token"
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return(session, nil)
+	sessionRepository.On("DeleteByRefreshToken", ctx, refreshToken).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, userUuid).Return(user, nil)
+	timeProvider.On("Now").Return(fakeNow)
+	uuidProvider.On("Random").Return(newRefreshToken).Once()
+	uuidProvider.On("Random").Return(tokenUuid).Once()
+	sessionRepository.On("Create", ctx, newSession).Return(nil)
+	jwtManager.On("Generate", authInfo).Return(accessToken, nil)
+
+	request := &auth.RefreshTokensRequest{RefreshToken: refreshToken.String(), ClientIp: clientIp, UserAgent: userAgent}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.RefreshTokens(ctx, request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.RefreshTokensResponse{RefreshToken: newRefreshToken.String(), AccessToken: accessToken}, response)
+	sessionRepository.AssertCalled(t, "DeleteByRefreshToken", ctx, refreshToken)
+	sessionRepository.AssertCalled(t, "Create", ctx, newSession)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_RefreshTokens_PasswordIsExpired(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime = time.Hour
+	const refreshTokenLifetime = 720 * time.Hour
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid := uuid.MustParse("b5b1d9f4-1d0e-4c0b-9b1e-3f6f1e6c2a10")
+	sessionUuid := uuid.MustParse("7c1a2b3c-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+	refreshToken := uuid.MustParse("0f9e8d7c-6b5a-4938-a7b6-c5d4e3f2a1b0")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	createdAt := fakeNow.Add(-24 * time.Hour)
+	clientIp := "127.0.0.1"
+	userAgent := "grpc-go/1.71.1"
+	session := entities.NewSession(refreshToken, userUuid, fakeNow.Add(time.Hour), sessionUuid, createdAt, createdAt, clientIp, userAgent)
+	ctx := context.TODO()
+
+	user := entities.NewUser(userUuid, createdAt, "Name", "name", "hash", "salt", "pepper", fakeNow.Add(-maxPasswordAge-time.Second), time.Time{})
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return(session, nil)
+	sessionRepository.On("DeleteByRefreshToken", ctx, refreshToken).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, userUuid).Return(user, nil)
+	timeProvider.On("Now").Return(fakeNow)
+	request := &auth.RefreshTokensRequest{RefreshToken: refreshToken.String(), ClientIp: clientIp, UserAgent: userAgent}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	response, err := service.RefreshTokens(ctx, request)
+
+	// Assert
+	assert.Nil(t, response)
+	assert.EqualError(t, err, "password is expired, log in to change it")
+	sessionRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	jwtManager.AssertNotCalled(t, "Generate", mock.Anything)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+	unitOfWork.AssertNotCalled(t, "Save", ctx)
+}
+
+func Test_DeleteAllSessions_KeepsCurrentSession(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	refreshToken, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+	sessionRepository.On("DeleteByUserUuid", ctx, fakeUuid, refreshToken).Return(nil)
+
+	request := &auth.DeleteAllSessionsRequest{AccessToken: accessToken, RefreshToken: refreshToken.String()}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteAllSessions(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	sessionRepository.AssertCalled(t, "DeleteByUserUuid", ctx, fakeUuid, refreshToken)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_DeleteAllSessions_AccessTokenIsExpired(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	tokenError := &services.TokenError{Reason: services.TokenExpired, Message: "token is expired"}
+	jwtManager.On("Parse", accessToken).Return((*value_objects.AuthInfo)(nil), tokenError)
+
+	request := &auth.DeleteAllSessionsRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteAllSessions(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Equal(t, tokenError, err)
+	assert.Empty(t, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_DeleteAllSessions_AccessTokenIsRestricted(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow, Scope: value_objects.PasswordChangeScope}
+	accessToken := "Fake restricted access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+
+	request := &auth.DeleteAllSessionsRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteAllSessions(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "access token only allows to change the password")
+	assert.Empty(t, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_ListSessions_HidesRefreshTokensAndExpiredSessions(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	older := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 1, 0, time.UTC)
+	newer := time.Date(2025, 4, 8, 14, 39, 2, 0, time.UTC)
+	refreshToken, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	sessionUuid, _ := uuid.Parse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	activeSession := entities.NewSession(refreshToken, fakeUuid, newer, sessionUuid, older, fakeNow, "127.0.0.1", "grpc-go/1.71.1")
+	expiredSession := entities.NewSession(fakeUuid, fakeUuid, older, fakeUuid, older, older, "127.0.0.2", "grpc-go/1.71.1")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: newer}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+	sessionRepository.On("GetAllByUserUuid", ctx, fakeUuid).Return([]*entities.Session{activeSession, expiredSession}, nil)
+
+	request := &auth.ListSessionsRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.ListSessionsResponse{Sessions: []auth.SessionInfo{{
+		Id:           sessionUuid.String(),
+		CreatedAt:    older,
+		RefreshedAt:  fakeNow,
+		ExpirationAt: newer,
+		ClientIp:     "127.0.0.1",
+		UserAgent:    "grpc-go/1.71.1",
+	}}}
+
+	// Act
+	actualResponse, err := service.ListSessions(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	sessionRepository.AssertCalled(t, "GetAllByUserUuid", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_ListSessions_TokenIsIssuedBeforeInvalidation(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	tokensValidAfter := fakeNow.Add(-time.Minute)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow.Add(time.Hour), IssuedAt: tokensValidAfter.Add(-time.Second)}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(tokensValidAfter, true, nil)
+
+	request := &auth.ListSessionsRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.ListSessions(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	var tokenError *services.TokenError
+	assert.ErrorAs(t, err, &tokenError)
+	assert.Equal(t, services.TokenRevoked, tokenError.Reason)
+	assert.Nil(t, actualResponse)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+	sessionRepository.AssertNotCalled(t, "GetAllByUserUuid", ctx, fakeUuid)
+}
+
+func Test_DeleteSessionById_SessionDoesNotBelongToUser(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	sessionUuid, _ := uuid.Parse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+	sessionRepository.On("TryDeleteByUuid", ctx, sessionUuid, fakeUuid).Return(false, nil)
+
+	request := &auth.DeleteSessionByIdRequest{AccessToken: accessToken, SessionId: sessionUuid.String()}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.DeleteSessionById(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "session not found")
+	assert.Empty(t, actualResponse)
+	sessionRepository.AssertCalled(t, "TryDeleteByUuid", ctx, sessionUuid, fakeUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_GetCurrentUser_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(userUuid, fakeNow, "Name", "name", "password hash", "salt", "pepper", fakeNow, time.Time{})
+	authInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, userUuid).Return(user, nil)
+
+	request := &auth.GetCurrentUserRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.GetCurrentUserResponse{Uuid: userUuid.String(), Name: "Name", CreatedAt: fakeNow}
+
+	// Act
+	actualResponse, err := service.GetCurrentUser(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	jwtManager.AssertCalled(t, "Parse", accessToken)
+	userRepository.AssertCalled(t, "TryGetByUuid", ctx, userUuid)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_GetCurrentUser_TokenIsIssuedBeforeInvalidation(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(userUuid, fakeNow, "Name", "name", "password hash", "salt", "pepper", fakeNow, fakeNow.Add(500*time.Millisecond))
+	authInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: fakeNow.Add(time.Hour), IssuedAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, userUuid).Return(user, nil)
+
+	request := &auth.GetCurrentUserRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.GetCurrentUser(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	var tokenError *services.TokenError
+	assert.ErrorAs(t, err, &tokenError)
+	assert.Equal(t, services.TokenRevoked, tokenError.Reason)
+	assert.Empty(t, actualResponse)
+	// The cut-off is read from the user row the RPC loads anyway
+	userRepository.AssertNotCalled(t, "TryGetTokensValidAfter", ctx, userUuid)
+	unitOfWorkStarter.AssertNumberOfCalls(t, "Start", 1)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_GetCurrentUser_UserUuidIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return((*entities.User)(nil), nil)
+
+	request := &auth.GetCurrentUserRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.GetCurrentUser(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.EqualError(t, err, "user not found")
+	assert.Empty(t, actualResponse)
+	userRepository.AssertCalled(t, "TryGetByUuid", ctx, fakeUuid)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
+}
+
+func Test_GetJwks_ReturnsPublicKeys(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	keys := []value_objects.Jwk{{KeyType: "OKP", KeyId: "2025-04", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}}
+	ctx := context.TODO()
+
+	jwtManager.On("PublicKeys").Return(keys)
+
+	request := &auth.GetJwksRequest{}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.GetJwks(ctx, request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.GetJwksResponse{Keys: keys}, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_CheckAccessToken_TokenIsRevoked(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	tokenUuid := uuid.MustParse("6f1f4a8e-3c1b-4d2a-9e5f-0a7b8c9d0e1f")
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt, TokenUuid: tokenUuid}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	revokedTokenCache.On("Contains", tokenUuid).Return(true)
+
+	request := &auth.CheckAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := auth.CheckAccessTokenResponse{IsActive: false}
+
+	// Act
+	actualResponse, err := service.CheckAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, *actualResponse)
+	revokedTokenCache.AssertCalled(t, "Contains", tokenUuid)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_RevokeAccessToken_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	revokedTokenRepository := infrastructure.NewMockRevokedTokenRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	tokenUuid := uuid.MustParse("6f1f4a8e-3c1b-4d2a-9e5f-0a7b8c9d0e1f")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	fakeExpirationAt := fakeNow.Add(10 * time.Minute)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt, TokenUuid: tokenUuid}
+	revokedToken := entities.NewRevokedToken(tokenUuid, fakeUuid, fakeNow, fakeExpirationAt)
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	revokedTokenCache.On("Contains", tokenUuid).Return(false)
+	revokedTokenCache.On("Add", tokenUuid, fakeExpirationAt).Return()
+	timeProvider.On("Now").Return(fakeNow)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("RevokedTokenRepository").Return(revokedTokenRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, fakeUuid).Return(time.Time{}, true, nil)
+	revokedTokenRepository.On("Create", ctx, revokedToken).Return(nil)
+
+	request := &auth.RevokeAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.RevokeAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, actualResponse)
+	revokedTokenRepository.AssertCalled(t, "Create", ctx, revokedToken)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+	revokedTokenCache.AssertCalled(t, "Add", tokenUuid, fakeExpirationAt)
+}
+
+func Test_RevokeAccessToken_TokenHasNoId(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	fakeExpirationAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeExpirationAt}
+	accessToken := "Fake legacy access token"
+	ctx := context.TODO()
+
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+
+	request := &auth.RevokeAccessTokenRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.RevokeAccessToken(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.Nil(t, actualResponse)
+	assert.IsType(t, &services.InvariantViolationError{}, err)
+	revokedTokenCache.AssertNotCalled(t, "Contains", mock.Anything)
+	unitOfWork.AssertNotCalled(t, "RevokedTokenRepository")
+}
+
+func Test_RevokeAllAccessTokens_IsValid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	user := entities.NewUser(fakeUuid, createdAt, "Name", "name", "password hash", "salt", "pepper", createdAt, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, createdAt, "Name", "name", "password hash", "salt", "pepper", createdAt, fakeNow)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+
+	request := &auth.RevokeAllAccessTokensRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.RevokeAllAccessTokens(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_RevokeAllAccessTokens_StoresFullPrecision(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	fakeUuid := uuid.Nil
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 700_000_000, time.UTC)
+	user := entities.NewUser(fakeUuid, createdAt, "Name", "name", "password hash", "salt", "pepper", createdAt, time.Time{})
+	expectedUser := entities.NewUser(fakeUuid, createdAt, "Name", "name", "password hash", "salt", "pepper", createdAt, fakeNow)
+	authInfo := &value_objects.AuthInfo{UserUuid: fakeUuid, ExpirationAt: fakeNow.Add(time.Hour)}
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	timeProvider.On("Now").Return(fakeNow)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetByUuid", ctx, fakeUuid).Return(user, nil)
+	userRepository.On("TryUpdate", ctx, expectedUser).Return(true, nil)
+
+	request := &auth.RevokeAllAccessTokensRequest{AccessToken: accessToken}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.RevokeAllAccessTokens(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualResponse)
+	userRepository.AssertCalled(t, "TryUpdate", ctx, expectedUser)
+}
+
+func Test_Introspect_ClientIsUnauthenticated(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "wrong_secret").Return(false)
+
+	request := &auth.IntrospectRequest{Token: accessToken, ClientId: "gateway", ClientSecret: "wrong_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	var tokenError *services.TokenError
+	assert.ErrorAs(t, err, &tokenError)
+	assert.Equal(t, services.ClientUnauthenticated, tokenError.Reason)
+	assert.Nil(t, actualResponse)
+	jwtManager.AssertNotCalled(t, "Parse", accessToken)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_Introspect_AccessTokenIsActive(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = true
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	userRepository := infrastructure.NewMockUserRepository()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid := uuid.MustParse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	tokenUuid := uuid.MustParse("6f1f4a8e-3c1b-4d2a-9e5f-0a7b8c9d0e1f")
+	sessionUuid := uuid.MustParse("7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d")
+	issuedAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	expirationAt := issuedAt.Add(time.Hour)
+	createdAt := issuedAt.Add(-24 * time.Hour)
+	authInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, TokenUuid: tokenUuid, SessionUuid: sessionUuid, IssuedAt: issuedAt}
+	session := entities.NewSession(uuid.New(), userUuid, createdAt.Add(720*time.Hour), sessionUuid, createdAt, issuedAt, "127.0.0.1", "grpc-go/1.71.1")
+	accessToken := "Fake access token"
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "gateway_secret").Return(true)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	revokedTokenCache.On("Contains", tokenUuid).Return(false)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("UserRepository").Return(userRepository)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	userRepository.On("TryGetTokensValidAfter", ctx, userUuid).Return(time.Time{}, true, nil)
+	sessionRepository.On("TryGetByUuid", ctx, sessionUuid).Return(session, nil)
+
+	request := &auth.IntrospectRequest{Token: accessToken, ClientId: "gateway", ClientSecret: "gateway_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := &auth.IntrospectResponse{
+		Active:       true,
+		Subject:      userUuid.String(),
+		ExpirationAt: expirationAt,
+		IssuedAt:     issuedAt,
+		TokenType:    auth.AccessTokenType,
+		Session: &auth.SessionInfo{
+			Id:           sessionUuid.String(),
+			CreatedAt:    createdAt,
+			RefreshedAt:  issuedAt,
+			ExpirationAt: createdAt.Add(720 * time.Hour),
+			ClientIp:     "127.0.0.1",
+			UserAgent:    "grpc-go/1.71.1",
+		},
+	}
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, actualResponse)
+	sessionRepository.AssertCalled(t, "TryGetByUuid", ctx, sessionUuid)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_Introspect_AccessTokenIsRestricted(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid := uuid.MustParse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	tokenUuid := uuid.MustParse("6f1f4a8e-3c1b-4d2a-9e5f-0a7b8c9d0e1f")
+	issuedAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	authInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: issuedAt.Add(10 * time.Minute), Scope: value_objects.PasswordChangeScope, TokenUuid: tokenUuid, IssuedAt: issuedAt}
+	accessToken := "Fake password change token"
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "gateway_secret").Return(true)
+	jwtManager.On("Parse", accessToken).Return(authInfo, nil)
+	revokedTokenCache.On("Contains", tokenUuid).Return(false)
+
+	request := &auth.IntrospectRequest{Token: accessToken, ClientId: "gateway", ClientSecret: "gateway_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.IntrospectResponse{}, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_Introspect_AccessTokenIsInvalid(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	accessToken := "Fake malformed access token"
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "gateway_secret").Return(true)
+	tokenError := &services.TokenError{Reason: services.TokenMalformed, Message: "token is malformed"}
+	jwtManager.On("Parse", accessToken).Return((*value_objects.AuthInfo)(nil), tokenError)
+
+	request := &auth.IntrospectRequest{Token: accessToken, ClientId: "gateway", ClientSecret: "gateway_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.IntrospectResponse{Active: false}, actualResponse)
+	unitOfWorkStarter.AssertNotCalled(t, "Start", ctx)
+}
+
+func Test_Introspect_RefreshTokenIsActive(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	userUuid := uuid.MustParse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	refreshToken := uuid.MustParse("2b3c4d5e-6f7a-4b2c-9d3e-4f5a6b7c8d9e")
+	sessionUuid := uuid.MustParse("7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d")
+	fakeNow := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	createdAt := fakeNow.Add(-24 * time.Hour)
+	refreshedAt := fakeNow.Add(-time.Hour)
+	expirationAt := refreshedAt.Add(720 * time.Hour)
+	session := entities.NewSession(refreshToken, userUuid, expirationAt, sessionUuid, createdAt, refreshedAt, "127.0.0.1", "grpc-go/1.71.1")
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "gateway_secret").Return(true)
+	timeProvider.On("Now").Return(fakeNow)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return(session, nil)
+
+	request := &auth.IntrospectRequest{Token: refreshToken.String(), ClientId: "gateway", ClientSecret: "gateway_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+	expectedResponse := &auth.IntrospectResponse{
+		Active:       true,
+		Subject:      userUuid.String(),
+		ExpirationAt: expirationAt,
+		IssuedAt:     refreshedAt,
+		TokenType:    auth.RefreshTokenType,
+		Session: &auth.SessionInfo{
+			Id:           sessionUuid.String(),
+			CreatedAt:    createdAt,
+			RefreshedAt:  refreshedAt,
+			ExpirationAt: expirationAt,
+			ClientIp:     "127.0.0.1",
+			UserAgent:    "grpc-go/1.71.1",
+		},
+	}
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResponse, actualResponse)
+	jwtManager.AssertNotCalled(t, "Parse", mock.Anything)
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
+
+func Test_Introspect_RefreshTokenDoesNotExist(t *testing.T) {
+	// Arrange
+	const accessTokenLifetime time.Duration = 0
+	const refreshTokenLifetime time.Duration = 0
+	const passwordChangeTokenLifetime time.Duration = 0
+	const maxPasswordAge = 90 * 24 * time.Hour
+	const passwordHistoryDepth = 3
+	const checkAccessTokenSession = false
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	sessionRepository := infrastructure.NewMockSessionRepository()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	hasher := infrastructure.NewMockHasher()
+	salter := infrastructure.NewMockSalter()
+	pepperer := infrastructure.NewMockPepperer()
+	usernamePolicy := infrastructure.NewMockUsernamePolicy()
+	passwordPolicy := infrastructure.NewMockPasswordPolicy()
+	breachedPasswordChecker := infrastructure.NewMockBreachedPasswordChecker()
+	jwtManager := infrastructure.NewMockJwtManager()
+	revokedTokenCache := infrastructure.NewMockRevokedTokenCache()
+	clientAuthenticator := infrastructure.NewMockClientAuthenticator()
+
+	refreshToken := uuid.MustParse("2b3c4d5e-6f7a-4b2c-9d3e-4f5a6b7c8d9e")
+	ctx := context.TODO()
+
+	clientAuthenticator.On("Authenticate", "gateway", "gateway_secret").Return(true)
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SessionRepository").Return(sessionRepository)
+	unitOfWork.On("Rollback", ctx).Return(nil)
+	sessionRepository.On("TryGetByRefreshToken", ctx, refreshToken).Return((*entities.Session)(nil), nil)
+
+	request := &auth.IntrospectRequest{Token: refreshToken.String(), ClientId: "gateway", ClientSecret: "gateway_secret"}
+	service := auth.NewRealService(accessTokenLifetime, refreshTokenLifetime, passwordChangeTokenLifetime, maxPasswordAge, passwordHistoryDepth, checkAccessTokenSession, unitOfWorkStarter, timeProvider, uuidProvider, hasher, salter, pepperer, usernamePolicy, passwordPolicy, breachedPasswordChecker, jwtManager, revokedTokenCache, clientAuthenticator)
+
+	// Act
+	actualResponse, err := service.Introspect(ctx, request)
+	t.Log(actualResponse)
+	t.Log(err)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.IntrospectResponse{Active: false}, actualResponse)
+	unitOfWork.AssertCalled(t, "Rollback", ctx)
 }
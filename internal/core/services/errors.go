@@ -1,5 +1,7 @@
 package services
 
+import "grpc-auth/internal/core/value-objects"
+
 type InvariantViolationError struct {
 	Message string
 }
@@ -7,3 +9,43 @@ type InvariantViolationError struct {
 func (e *InvariantViolationError) Error() string {
 	return e.Message
 }
+
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+type PolicyError struct {
+	Field      string
+	Violations []value_objects.Violation
+}
+
+func (e *PolicyError) Error() string {
+	return e.Field + " does not satisfy the policy"
+}
+
+// Reasons of TokenError. Signature, algorithm and key failures hint at forged tokens, expiry is part of normal operation.
+const (
+	TokenMalformed        = "TOKEN_MALFORMED"
+	TokenSignatureInvalid = "TOKEN_SIGNATURE_INVALID"
+	TokenAlgorithmInvalid = "TOKEN_ALGORITHM_INVALID"
+	TokenKeyUnknown       = "TOKEN_KEY_UNKNOWN"
+	TokenExpired          = "TOKEN_EXPIRED"
+	TokenNotYetValid      = "TOKEN_NOT_YET_VALID"
+	TokenClaimsInvalid    = "TOKEN_CLAIMS_INVALID"
+	TokenRevoked          = "TOKEN_REVOKED"
+	// ClientUnauthenticated rejects the caller of Introspect, not the token
+	ClientUnauthenticated = "CLIENT_UNAUTHENTICATED"
+)
+
+type TokenError struct {
+	Reason  string
+	Message string
+}
+
+func (e *TokenError) Error() string {
+	return e.Message
+}
@@ -17,11 +17,37 @@ type TimeProvider interface {
 }
 
 type Salter interface {
-	Salt(uuid uuid.UUID, createdAt time.Time, name, password string) string
+	Generate() string
+	Salt(salt, password string) string
+	LegacySalt(uuid uuid.UUID, createdAt time.Time, name, password string) string
+}
+
+type Pepperer interface {
+	ActiveId() string
+	Pepper(pepperId, saltedPassword string) (string, bool)
 }
 
 type Hasher interface {
 	Hash(saltedPassword string) string
+	Verify(saltedPassword, hash string) bool
+	NeedsRehash(hash string) bool
+}
+
+type UsernamePolicy interface {
+	Normalize(name string) string
+	Check(name string) []value_objects.Violation
+}
+
+type PasswordPolicy interface {
+	Check(name, password string) []value_objects.Violation
+}
+
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+type ClientAuthenticator interface {
+	Authenticate(clientId, clientSecret string) bool
 }
 
 type UnitOfWorkStarter interface {
@@ -31,6 +57,9 @@ type UnitOfWorkStarter interface {
 type UnitOfWork interface {
 	UserRepository() UserRepository
 	SessionRepository() SessionRepository
+	PasswordHistoryRepository() PasswordHistoryRepository
+	SigningKeyRepository() SigningKeyRepository
+	RevokedTokenRepository() RevokedTokenRepository
 
 	Save(ctx context.Context) error
 	Rollback(ctx context.Context) error
@@ -38,18 +67,54 @@ type UnitOfWork interface {
 
 type UserRepository interface {
 	TryCreate(ctx context.Context, user *entities.User) (bool, error)
-	TryGetByName(ctx context.Context, name string) (*entities.User, error)
+	TryGetByName(ctx context.Context, normalizedName string) (*entities.User, error)
+	TryGetByUuid(ctx context.Context, userUuid uuid.UUID) (*entities.User, error)
+	TryUpdate(ctx context.Context, user *entities.User) (bool, error)
 	TryDelete(ctx context.Context, userUuid uuid.UUID) (bool, error)
-	Exists(ctx context.Context, userUuid uuid.UUID) (bool, error)
+	TryGetTokensValidAfter(ctx context.Context, userUuid uuid.UUID) (time.Time, bool, error)
 }
 
 type SessionRepository interface {
 	Create(ctx context.Context, session *entities.Session) error
 	TryGetByRefreshToken(ctx context.Context, refreshToken uuid.UUID) (*entities.Session, error)
+	GetAllByUserUuid(ctx context.Context, userUuid uuid.UUID) ([]*entities.Session, error)
 	DeleteByRefreshToken(ctx context.Context, refreshToken uuid.UUID) error
+	DeleteByUserUuid(ctx context.Context, userUuid, keptRefreshToken uuid.UUID) error
+	TryDeleteByUuid(ctx context.Context, sessionUuid, userUuid uuid.UUID) (bool, error)
+	ExistsByUuid(ctx context.Context, sessionUuid uuid.UUID) (bool, error)
+	TryGetByUuid(ctx context.Context, sessionUuid uuid.UUID) (*entities.Session, error)
+}
+
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *entities.PasswordHistoryEntry) error
+	GetLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, limit int) ([]*entities.PasswordHistoryEntry, error)
+	DeleteAllButLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, kept int) error
+}
+
+type SigningKeyRepository interface {
+	Lock(ctx context.Context) error
+	Create(ctx context.Context, key *entities.SigningKey) error
+	GetAll(ctx context.Context) ([]*entities.SigningKey, error)
+	Update(ctx context.Context, key *entities.SigningKey) error
+	DeleteRetired(ctx context.Context, now time.Time) error
+}
+
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *entities.RevokedToken) error
+	GetAll(ctx context.Context) ([]*entities.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// RevokedTokenCache keeps the revoked tokens in memory, so checking a token does not need the database.
+type RevokedTokenCache interface {
+	Add(tokenUuid uuid.UUID, expirationAt time.Time)
+	Contains(tokenUuid uuid.UUID) bool
 }
 
 type JwtManager interface {
 	Generate(info *value_objects.AuthInfo) (string, error)
-	Parse(tokenString string) *value_objects.AuthInfo
+	// Parse returns a *TokenError when the token is rejected.
+	Parse(tokenString string) (*value_objects.AuthInfo, error)
+	// PublicKeys returns the keys that verify tokens and may be published. HMAC secrets are never returned.
+	PublicKeys() []value_objects.Jwk
 }
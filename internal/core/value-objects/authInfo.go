@@ -5,7 +5,14 @@ import (
 	"time"
 )
 
+// PasswordChangeScope restricts a token to changing an expired password.
+const PasswordChangeScope = "password_change"
+
 type AuthInfo struct {
 	UserUuid     uuid.UUID
 	ExpirationAt time.Time
+	Scope        string    // Empty for unrestricted tokens
+	TokenUuid    uuid.UUID // The jti claim, nil for tokens issued before token ids
+	SessionUuid  uuid.UUID // The sid claim, nil for tokens not issued for a session
+	IssuedAt     time.Time // The iat claim, only set by parsing, zero for tokens issued before registered claims
 }
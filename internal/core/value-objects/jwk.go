@@ -0,0 +1,14 @@
+package value_objects
+
+// Jwk is a public key in the JSON Web Key format of RFC 7517. Coordinates and RSA parameters are base64url encoded.
+type Jwk struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid,omitempty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
@@ -0,0 +1,6 @@
+package value_objects
+
+type Violation struct {
+	Reason      string
+	Description string
+}
@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+	// argon2idMaxMemory and argon2idMaxIterations cap the cost of a single verification, so neither a tampered hash nor
+	// concurrent logins can exhaust the memory or the CPU. The memory is in KiB, 256 MiB
+	argon2idMaxMemory     = 256 * 1024
+	argon2idMaxIterations = 10
+)
+
+// Argon2idHasher produces hashes in the PHC string format, e.g. "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>", so
+// the parameters and the random salt are stored together with every hash.
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) (*Argon2idHasher, error) {
+	err := validateArgon2idParams(memory, iterations, parallelism)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Argon2idHasher{memory, iterations, parallelism}, nil
+}
+
+func (h *Argon2idHasher) Hash(saltedPassword string) string {
+	salt := make([]byte, argon2idSaltLength)
+	_, _ = rand.Read(salt)
+
+	key := argon2.IDKey([]byte(saltedPassword), salt, h.iterations, h.memory, h.parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.memory,
+		h.iterations,
+		h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func (h *Argon2idHasher) Verify(saltedPassword, hash string) bool {
+	params, ok := parseArgon2idHash(hash)
+	if !ok {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(saltedPassword), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, ok := parseArgon2idHash(hash)
+	if !ok {
+		return true
+	}
+
+	return params.memory != h.memory ||
+		params.iterations != h.iterations ||
+		params.parallelism != h.parallelism ||
+		len(params.salt) != argon2idSaltLength ||
+		len(params.key) != argon2idKeyLength
+}
+
+func (h *Argon2idHasher) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func parseArgon2idHash(hash string) (*argon2idParams, bool) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, false
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return nil, false
+	}
+
+	params := &argon2idParams{}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism)
+	if err != nil || validateArgon2idParams(params.memory, params.iterations, params.parallelism) != nil {
+		return nil, false
+	}
+
+	params.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, false
+	}
+
+	params.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(params.key) == 0 {
+		return nil, false
+	}
+
+	return params, true
+}
+
+// validateArgon2idParams rejects parameters argon2.IDKey panics on or that can't be afforded
+func validateArgon2idParams(memory, iterations uint32, parallelism uint8) error {
+	if iterations < 1 || iterations > argon2idMaxIterations {
+		return fmt.Errorf("argon2id iterations must be between 1 and %d", argon2idMaxIterations)
+	}
+	if parallelism < 1 {
+		return fmt.Errorf("argon2id parallelism must be at least 1")
+	}
+	if memory < 8*uint32(parallelism) {
+		return fmt.Errorf("argon2id memory must be at least %d KiB for parallelism %d", 8*uint32(parallelism), parallelism)
+	}
+	if memory > argon2idMaxMemory {
+		return fmt.Errorf("argon2id memory must be at most %d KiB", argon2idMaxMemory)
+	}
+
+	return nil
+}
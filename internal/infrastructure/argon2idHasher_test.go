@@ -0,0 +1,112 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"strings"
+	"testing"
+)
+
+func newArgon2idHasher(t *testing.T, memory, iterations uint32, parallelism uint8) *infrastructure.Argon2idHasher {
+	hasher, err := infrastructure.NewArgon2idHasher(memory, iterations, parallelism)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hasher
+}
+
+func TestArgon2idHash(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hasher := newArgon2idHasher(t, 1024, 1, 1)
+
+	// Act
+	firstHash := hasher.Hash(saltedPassword)
+	secondHash := hasher.Hash(saltedPassword)
+
+	// Assert
+	t.Log("hash: ", firstHash)
+
+	assert.True(t, strings.HasPrefix(firstHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.NotEqual(t, firstHash, secondHash)
+}
+
+func TestArgon2idVerify(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hasher := newArgon2idHasher(t, 1024, 1, 1)
+	hash := hasher.Hash(saltedPassword)
+
+	// Act
+	valid := hasher.Verify(saltedPassword, hash)
+	invalid := hasher.Verify("wrong password + salt", hash)
+	malformed := hasher.Verify(saltedPassword, "$argon2id$v=19$m=1024")
+
+	// Assert
+	assert.True(t, valid)
+	assert.False(t, invalid)
+	assert.False(t, malformed)
+}
+
+func TestArgon2idVerify_UsesParametersFromHash(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hash := newArgon2idHasher(t, 1024, 1, 1).Hash(saltedPassword)
+	hasher := newArgon2idHasher(t, 2048, 2, 2)
+
+	// Act
+	valid := hasher.Verify(saltedPassword, hash)
+
+	// Assert
+	assert.True(t, valid)
+}
+
+func TestNewArgon2idHasher_InvalidParameters(t *testing.T) {
+	// Arrange
+	cases := []struct {
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+	}{
+		{1024, 0, 1},
+		{1024, 1, 0},
+		{8, 1, 2},
+		{256*1024 + 1, 1, 1},
+		{1024, 11, 1},
+	}
+
+	for _, c := range cases {
+		// Act
+		_, err := infrastructure.NewArgon2idHasher(c.memory, c.iterations, c.parallelism)
+
+		// Assert
+		assert.Error(t, err, "m=%d,t=%d,p=%d", c.memory, c.iterations, c.parallelism)
+	}
+}
+
+func TestArgon2idVerify_InvalidParametersInHash(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hasher := newArgon2idHasher(t, 1024, 1, 1)
+	hash := hasher.Hash(saltedPassword)
+	parameters := "m=1024,t=1,p=1"
+
+	// Act
+	zeroIterations := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=1024,t=0,p=1", 1))
+	zeroParallelism := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=1024,t=1,p=0", 1))
+	hugeMemory := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=4294967295,t=1,p=1", 1))
+	memoryAboveCap := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=262145,t=1,p=1", 1))
+	hugeIterations := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=1024,t=4294967295,p=1", 1))
+	iterationsAboveCap := hasher.Verify(saltedPassword, strings.Replace(hash, parameters, "m=1024,t=11,p=1", 1))
+	needsRehash := hasher.NeedsRehash(strings.Replace(hash, parameters, "m=1024,t=0,p=1", 1))
+
+	// Assert
+	assert.False(t, zeroIterations)
+	assert.False(t, zeroParallelism)
+	assert.False(t, hugeMemory)
+	assert.False(t, memoryAboveCap)
+	assert.False(t, hugeIterations)
+	assert.False(t, iterationsAboveCap)
+	assert.True(t, needsRehash)
+}
@@ -0,0 +1,137 @@
+package infrastructure
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A corpus line is a 40-character SHA-1, a colon and a count, so it never gets close to this length
+const maxCorpusLineLength = 64
+
+// FileBreachedPasswordChecker looks passwords up in a local copy of the Pwned Passwords corpus ordered by hash.
+// Every line has the format SHA1:COUNT, the file is binary searched by byte offsets without loading it into memory.
+type FileBreachedPasswordChecker struct {
+	file      *os.File
+	size      int64
+	threshold int
+}
+
+func NewFileBreachedPasswordChecker(path string, threshold int) (*FileBreachedPasswordChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	return &FileBreachedPasswordChecker{file, info.Size(), threshold}, nil
+}
+
+func (c *FileBreachedPasswordChecker) Close() error {
+	return c.file.Close()
+}
+
+// IsBreached reports whether the password appears in the corpus more than threshold times.
+func (c *FileBreachedPasswordChecker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	count, err := c.occurrences(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return count > c.threshold, nil
+}
+
+func (c *FileBreachedPasswordChecker) occurrences(hash string) (int, error) {
+	low, high := int64(0), c.size
+	for low < high {
+		middle := low + (high-low)/2
+
+		start, end, line, err := c.lineAt(middle)
+		if err != nil {
+			return 0, err
+		}
+
+		lineHash, lineCount, found := strings.Cut(line, ":")
+		if !found {
+			return 0, errors.New("breached password corpus line has invalid format")
+		}
+
+		switch strings.Compare(strings.ToUpper(lineHash), hash) {
+		case 0:
+			return strconv.Atoi(lineCount)
+		case -1:
+			low = end
+		default:
+			high = start
+		}
+	}
+
+	return 0, nil
+}
+
+// lineAt returns the bounds of the line containing offset, the end includes the line break.
+func (c *FileBreachedPasswordChecker) lineAt(offset int64) (int64, int64, string, error) {
+	bufferStart := max(offset-maxCorpusLineLength, 0)
+	bufferEnd := min(offset+maxCorpusLineLength, c.size)
+	buffer := make([]byte, bufferEnd-bufferStart)
+	_, err := c.file.ReadAt(buffer, bufferStart)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, "", err
+	}
+
+	relativeOffset := int(offset - bufferStart)
+
+	lineStart := strings.LastIndexByte(string(buffer[:relativeOffset]), '\n') + 1
+	if lineStart == 0 && bufferStart != 0 {
+		return 0, 0, "", errors.New("breached password corpus line is too long")
+	}
+
+	lineEnd := len(buffer)
+	if index := strings.IndexByte(string(buffer[relativeOffset:]), '\n'); index != -1 {
+		lineEnd = relativeOffset + index + 1
+	} else if bufferEnd != c.size {
+		return 0, 0, "", errors.New("breached password corpus line is too long")
+	}
+
+	line := strings.TrimRight(string(buffer[lineStart:lineEnd]), "\r\n")
+
+	return bufferStart + int64(lineStart), bufferStart + int64(lineEnd), line, nil
+}
+
+// DisabledBreachedPasswordChecker is used when no corpus is configured.
+type DisabledBreachedPasswordChecker struct{}
+
+func NewDisabledBreachedPasswordChecker() *DisabledBreachedPasswordChecker {
+	return &DisabledBreachedPasswordChecker{}
+}
+
+func (c *DisabledBreachedPasswordChecker) IsBreached(_ string) (bool, error) {
+	return false, nil
+}
+
+type MockBreachedPasswordChecker struct {
+	mock.Mock
+}
+
+func NewMockBreachedPasswordChecker() *MockBreachedPasswordChecker {
+	return &MockBreachedPasswordChecker{}
+}
+
+func (c *MockBreachedPasswordChecker) IsBreached(password string) (bool, error) {
+	args := c.Called(password)
+	return args.Bool(0), args.Error(1)
+}
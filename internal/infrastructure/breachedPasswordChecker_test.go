@@ -0,0 +1,70 @@
+package infrastructure_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeCorpus(t *testing.T, counts map[string]int) string {
+	var lines []string
+	for password, count := range counts {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, fmt.Sprintf("%s:%d\r\n", strings.ToUpper(hex.EncodeToString(sum[:])), count))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0600)
+	assert.NoError(t, err)
+
+	return path
+}
+
+func TestBreachedPasswordCheckerIsBreached(t *testing.T) {
+	// Arrange
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[fmt.Sprintf("password%d", i)] = i + 1
+	}
+	checker, err := infrastructure.NewFileBreachedPasswordChecker(writeCorpus(t, counts), 10)
+	assert.NoError(t, err)
+	defer checker.Close()
+
+	// Act
+	frequent, frequentErr := checker.IsBreached("password500")
+	rare, rareErr := checker.IsBreached("password9")
+	absent, absentErr := checker.IsBreached("correct horse battery staple")
+
+	// Assert
+	assert.NoError(t, frequentErr)
+	assert.True(t, frequent)
+	assert.NoError(t, rareErr)
+	assert.False(t, rare)
+	assert.NoError(t, absentErr)
+	assert.False(t, absent)
+}
+
+func TestBreachedPasswordCheckerIsBreached_EveryEntryIsFound(t *testing.T) {
+	// Arrange
+	counts := map[string]int{"first": 5, "second": 7, "third": 9}
+	checker, err := infrastructure.NewFileBreachedPasswordChecker(writeCorpus(t, counts), 0)
+	assert.NoError(t, err)
+	defer checker.Close()
+
+	for password := range counts {
+		// Act
+		breached, err := checker.IsBreached(password)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, breached, password)
+	}
+}
@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+)
+
+// StaticClientAuthenticator checks the credentials of the resource servers allowed to introspect tokens. Secrets are
+// compared by their SHA-256 digests in constant time, so neither their content nor their length leaks.
+type StaticClientAuthenticator struct {
+	secretDigests map[string][sha256.Size]byte
+}
+
+// NewStaticClientAuthenticator expects secrets by client id. Without any client every caller is rejected.
+func NewStaticClientAuthenticator(secrets map[string]string) (*StaticClientAuthenticator, error) {
+	secretDigests := make(map[string][sha256.Size]byte, len(secrets))
+	for id, secret := range secrets {
+		if id == "" {
+			return nil, fmt.Errorf("client id must not be empty")
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("secret of client %q must not be empty", id)
+		}
+
+		secretDigests[id] = sha256.Sum256([]byte(secret))
+	}
+
+	return &StaticClientAuthenticator{secretDigests}, nil
+}
+
+func (a *StaticClientAuthenticator) Authenticate(clientId, clientSecret string) bool {
+	expected, ok := a.secretDigests[clientId]
+	actual := sha256.Sum256([]byte(clientSecret))
+
+	return subtle.ConstantTimeCompare(expected[:], actual[:]) == 1 && ok
+}
+
+type MockClientAuthenticator struct {
+	mock.Mock
+}
+
+func NewMockClientAuthenticator() *MockClientAuthenticator {
+	return &MockClientAuthenticator{}
+}
+
+func (a *MockClientAuthenticator) Authenticate(clientId, clientSecret string) bool {
+	args := a.Called(clientId, clientSecret)
+	return args.Bool(0)
+}
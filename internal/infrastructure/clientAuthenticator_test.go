@@ -0,0 +1,45 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+)
+
+func TestClientAuthenticate(t *testing.T) {
+	// Arrange
+	authenticator, err := infrastructure.NewStaticClientAuthenticator(map[string]string{"gateway": "gateway_secret"})
+	assert.NoError(t, err)
+
+	// Act
+	valid := authenticator.Authenticate("gateway", "gateway_secret")
+	wrongSecret := authenticator.Authenticate("gateway", "gateway_secret_")
+	unknownClient := authenticator.Authenticate("billing", "gateway_secret")
+	empty := authenticator.Authenticate("", "")
+
+	// Assert
+	assert.True(t, valid)
+	assert.False(t, wrongSecret)
+	assert.False(t, unknownClient)
+	assert.False(t, empty)
+}
+
+func TestClientAuthenticate_NoClients(t *testing.T) {
+	// Arrange
+	authenticator, err := infrastructure.NewStaticClientAuthenticator(nil)
+	assert.NoError(t, err)
+
+	// Act
+	valid := authenticator.Authenticate("", "")
+
+	// Assert
+	assert.False(t, valid)
+}
+
+func TestNewStaticClientAuthenticator_SecretIsEmpty(t *testing.T) {
+	// Act
+	_, err := infrastructure.NewStaticClientAuthenticator(map[string]string{"gateway": ""})
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package infrastructure
+
+import "grpc-auth/internal/core/services"
+
+// HashAlgorithm is a hasher that can tell its own hashes from the hashes of other algorithms.
+type HashAlgorithm interface {
+	services.Hasher
+	Recognizes(hash string) bool
+}
+
+// CompositeHasher hashes with the current algorithm and verifies with whichever algorithm produced the stored hash.
+// Hashes of the legacy algorithms always need a rehash.
+type CompositeHasher struct {
+	current HashAlgorithm
+	legacy  []HashAlgorithm
+}
+
+func NewCompositeHasher(current HashAlgorithm, legacy ...HashAlgorithm) *CompositeHasher {
+	return &CompositeHasher{current, legacy}
+}
+
+func (h *CompositeHasher) Hash(saltedPassword string) string {
+	return h.current.Hash(saltedPassword)
+}
+
+func (h *CompositeHasher) Verify(saltedPassword, hash string) bool {
+	if h.current.Recognizes(hash) {
+		return h.current.Verify(saltedPassword, hash)
+	}
+
+	for _, algorithm := range h.legacy {
+		if algorithm.Recognizes(hash) {
+			return algorithm.Verify(saltedPassword, hash)
+		}
+	}
+
+	return false
+}
+
+func (h *CompositeHasher) NeedsRehash(hash string) bool {
+	return !h.current.Recognizes(hash) || h.current.NeedsRehash(hash)
+}
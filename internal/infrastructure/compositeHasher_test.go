@@ -0,0 +1,65 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+)
+
+func TestCompositeHasherVerify_LegacyHash(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	legacyHash := infrastructure.NewSha512Hasher().Hash(saltedPassword)
+	hasher := infrastructure.NewCompositeHasher(newArgon2idHasher(t, 1024, 1, 1), infrastructure.NewSha512Hasher())
+
+	// Act
+	valid := hasher.Verify(saltedPassword, legacyHash)
+	invalid := hasher.Verify("wrong password + salt", legacyHash)
+	needsRehash := hasher.NeedsRehash(legacyHash)
+
+	// Assert
+	assert.True(t, valid)
+	assert.False(t, invalid)
+	assert.True(t, needsRehash)
+}
+
+func TestCompositeHasherVerify_CurrentHash(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hasher := infrastructure.NewCompositeHasher(newArgon2idHasher(t, 1024, 1, 1), infrastructure.NewSha512Hasher())
+	hash := hasher.Hash(saltedPassword)
+
+	// Act
+	valid := hasher.Verify(saltedPassword, hash)
+	needsRehash := hasher.NeedsRehash(hash)
+
+	// Assert
+	assert.True(t, valid)
+	assert.False(t, needsRehash)
+}
+
+func TestCompositeHasherNeedsRehash_OutdatedParameters(t *testing.T) {
+	// Arrange
+	saltedPassword := "password + salt"
+	hash := newArgon2idHasher(t, 1024, 1, 1).Hash(saltedPassword)
+	hasher := infrastructure.NewCompositeHasher(newArgon2idHasher(t, 2048, 1, 1), infrastructure.NewSha512Hasher())
+
+	// Act
+	valid := hasher.Verify(saltedPassword, hash)
+	needsRehash := hasher.NeedsRehash(hash)
+
+	// Assert
+	assert.True(t, valid)
+	assert.True(t, needsRehash)
+}
+
+func TestCompositeHasherVerify_UnknownHash(t *testing.T) {
+	// Arrange
+	hasher := infrastructure.NewCompositeHasher(newArgon2idHasher(t, 1024, 1, 1), infrastructure.NewSha512Hasher())
+
+	// Act
+	valid := hasher.Verify("password + salt", "$2a$10$unknown")
+
+	// Assert
+	assert.False(t, valid)
+}
@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/hex"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,6 +19,24 @@ func (h *Sha512Hasher) Hash(saltedPassword string) string {
 	return hex.EncodeToString(checksum[:])
 }
 
+func (h *Sha512Hasher) Verify(saltedPassword, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(h.Hash(saltedPassword)), []byte(hash)) == 1
+}
+
+func (h *Sha512Hasher) NeedsRehash(hash string) bool {
+	return !h.Recognizes(hash)
+}
+
+func (h *Sha512Hasher) Recognizes(hash string) bool {
+	if len(hash) != 2*sha512.Size {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+
+	return err == nil
+}
+
 type MockHasher struct {
 	mock.Mock
 }
@@ -30,3 +49,13 @@ func (h *MockHasher) Hash(saltedPassword string) string {
 	args := h.Called(saltedPassword)
 	return args.String(0)
 }
+
+func (h *MockHasher) Verify(saltedPassword, hash string) bool {
+	args := h.Called(saltedPassword, hash)
+	return args.Bool(0)
+}
+
+func (h *MockHasher) NeedsRehash(hash string) bool {
+	args := h.Called(hash)
+	return args.Bool(0)
+}
@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"grpc-auth/internal/core/value-objects"
+	"math/big"
+	"os"
+	"time"
+)
+
+// JwtKey is one key of the key ring. Verify-only keys have no signing key.
+type JwtKey struct {
+	Id              string
+	Method          jwt.SigningMethod
+	SigningKey      any
+	VerificationKey any
+	RetiresAt       time.Time // Zero for keys that never retire
+}
+
+func NewHmacJwtKey(id string, secret []byte, retiresAt time.Time) *JwtKey {
+	return &JwtKey{id, jwt.SigningMethodHS512, secret, secret, retiresAt}
+}
+
+// NewAsymmetricJwtKey parses a PEM-encoded private key, or a public key for a verify-only key.
+// Supported algorithms are ES256, RS256 and EdDSA.
+func NewAsymmetricJwtKey(id, algorithm string, keyPem []byte, retiresAt time.Time) (*JwtKey, error) {
+	var method jwt.SigningMethod
+	var privateKey crypto.Signer
+	var publicKey crypto.PublicKey
+	switch algorithm {
+	case jwt.SigningMethodES256.Alg():
+		method = jwt.SigningMethodES256
+		if key, err := jwt.ParseECPrivateKeyFromPEM(keyPem); err == nil {
+			privateKey = key
+		} else if publicKey, err = jwt.ParseECPublicKeyFromPEM(keyPem); err != nil {
+			return nil, err
+		}
+	case jwt.SigningMethodRS256.Alg():
+		method = jwt.SigningMethodRS256
+		if key, err := jwt.ParseRSAPrivateKeyFromPEM(keyPem); err == nil {
+			privateKey = key
+		} else if publicKey, err = jwt.ParseRSAPublicKeyFromPEM(keyPem); err != nil {
+			return nil, err
+		}
+	case jwt.SigningMethodEdDSA.Alg():
+		method = jwt.SigningMethodEdDSA
+		if key, err := jwt.ParseEdPrivateKeyFromPEM(keyPem); err == nil {
+			edKey, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("EdDSA requires an Ed25519 key")
+			}
+			privateKey = edKey
+		} else if publicKey, err = jwt.ParseEdPublicKeyFromPEM(keyPem); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	var signingKey any
+	if privateKey != nil {
+		signingKey = privateKey
+		publicKey = privateKey.Public()
+	}
+
+	err := validateJwtKeyStrength(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JwtKey{id, method, signingKey, publicKey, retiresAt}, nil
+}
+
+func validateJwtKeyStrength(publicKey crypto.PublicKey) error {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return errors.New("ES256 requires a P-256 key")
+		}
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return errors.New("RS256 requires a key of at least 2048 bits")
+		}
+	}
+
+	return nil
+}
+
+func (k *JwtKey) IsRetired(now time.Time) bool {
+	return !k.RetiresAt.IsZero() && !now.Before(k.RetiresAt)
+}
+
+// Jwk returns the public key in the JWK format. HMAC keys have no public part, so ok is false for them.
+func (k *JwtKey) Jwk() (jwk value_objects.Jwk, ok bool) {
+	jwk = value_objects.Jwk{KeyId: k.Id, Use: "sig", Algorithm: k.Method.Alg()}
+	switch key := k.VerificationKey.(type) {
+	case *ecdsa.PublicKey:
+		publicKey, err := key.ECDH()
+		if err != nil {
+			return jwk, false
+		}
+		// The uncompressed point is 0x04 followed by both coordinates of equal length
+		point := publicKey.Bytes()[1:]
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[:len(point)/2])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[len(point)/2:])
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	default:
+		return jwk, false
+	}
+
+	return jwk, true
+}
+
+type jwtKeyRingEntry struct {
+	Id        string    `json:"id"`
+	Algorithm string    `json:"algorithm"`
+	KeyPath   string    `json:"keyPath"`
+	Active    bool      `json:"active"`
+	RetiresAt time.Time `json:"retiresAt"`
+}
+
+// ReadJwtKeyRing reads a JSON array of keys like
+// [{"id": "2025-04", "algorithm": "ES256", "keyPath": "/keys/2025-04.pem", "active": true, "retiresAt": "2025-10-01T00:00:00Z"}].
+// HS512 key files contain the raw secret, other key files contain a PEM private key, or a public key for verify-only keys.
+func ReadJwtKeyRing(path string) (*JwtKey, []*JwtKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []jwtKeyRingEntry
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var activeKey *JwtKey
+	verificationKeys := make([]*JwtKey, 0, len(entries))
+	for _, entry := range entries {
+		keyMaterial, err := os.ReadFile(entry.KeyPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var key *JwtKey
+		if entry.Algorithm == jwt.SigningMethodHS512.Alg() {
+			key = NewHmacJwtKey(entry.Id, keyMaterial, entry.RetiresAt)
+		} else {
+			key, err = NewAsymmetricJwtKey(entry.Id, entry.Algorithm, keyMaterial, entry.RetiresAt)
+			if err != nil {
+				return nil, nil, fmt.Errorf("JWT key %q: %w", entry.Id, err)
+			}
+		}
+
+		if entry.Active {
+			if activeKey != nil {
+				return nil, nil, errors.New("JWT key ring has more than one active key")
+			}
+			activeKey = key
+		} else {
+			verificationKeys = append(verificationKeys, key)
+		}
+	}
+
+	if activeKey == nil {
+		return nil, nil, errors.New("JWT key ring has no active key")
+	}
+
+	return activeKey, verificationKeys, nil
+}
@@ -0,0 +1,277 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"grpc-auth/internal/core/entities"
+	"grpc-auth/internal/core/services"
+	"io"
+	"time"
+)
+
+const (
+	masterKeyLength  = 32
+	hmacSecretLength = 64
+	rsaKeyBits       = 3072
+)
+
+// JwtKeyRotator generates signing keys and keeps them encrypted with the master key in the signing_keys table.
+// A new key is created every rotation period and starts signing after the propagation delay, so verifiers can
+// learn its public key in advance. The replaced key retires once the last token it signed has expired, counting the
+// check interval other instances may keep signing with it and the leeway verifiers still accept it for.
+type JwtKeyRotator struct {
+	algorithm         string
+	masterKey         cipher.AEAD
+	rotationPeriod    time.Duration
+	propagationDelay  time.Duration
+	maxTokenLifetime  time.Duration
+	leeway            time.Duration
+	checkInterval     time.Duration
+	unitOfWorkStarter services.UnitOfWorkStarter
+	timeProvider      services.TimeProvider
+	uuidProvider      services.UuidProvider
+}
+
+func NewJwtKeyRotator(algorithm, encodedMasterKey string, rotationPeriod, propagationDelay, maxTokenLifetime, leeway, checkInterval time.Duration, unitOfWorkStarter services.UnitOfWorkStarter, timeProvider services.TimeProvider, uuidProvider services.UuidProvider) (*JwtKeyRotator, error) {
+	switch algorithm {
+	case jwt.SigningMethodHS512.Alg(), jwt.SigningMethodES256.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	if rotationPeriod <= propagationDelay {
+		return nil, errors.New("JWT key rotation period must be longer than the propagation delay")
+	}
+	// Other instances publish a new key up to one check interval after it is created, which the delay has to cover
+	if propagationDelay <= checkInterval {
+		return nil, errors.New("JWT key propagation delay must be longer than the rotation check interval")
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(encodedMasterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(masterKey) != masterKeyLength {
+		return nil, fmt.Errorf("JWT master key must be %d bytes long", masterKeyLength)
+	}
+
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JwtKeyRotator{algorithm, aead, rotationPeriod, propagationDelay, maxTokenLifetime, leeway, checkInterval, unitOfWorkStarter, timeProvider, uuidProvider}, nil
+}
+
+// Run rotates keys every check interval and passes them to the JWT manager until the context is done.
+func (r *JwtKeyRotator) Run(ctx context.Context, jwtManager *RealJwtManager, logger *zap.SugaredLogger) {
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			activeKey, verificationKeys, err := r.Rotate(ctx)
+			if err == nil {
+				err = jwtManager.SetKeys(activeKey, verificationKeys)
+			}
+			if err != nil {
+				logger.Errorw("JWT key rotation failed", "error", err)
+			}
+		}
+	}
+}
+
+// Rotate deletes retired keys, creates a new key when the newest one is older than the rotation period
+// and returns the decrypted key ring.
+func (r *JwtKeyRotator) Rotate(ctx context.Context) (*JwtKey, []*JwtKey, error) {
+	unitOfWork, err := r.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	signingKeyRepository := unitOfWork.SigningKeyRepository()
+
+	err = signingKeyRepository.Lock(ctx)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, nil, err
+	}
+
+	now := r.timeProvider.Now()
+
+	err = signingKeyRepository.DeleteRetired(ctx, now)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, nil, err
+	}
+
+	keys, err := signingKeyRepository.GetAll(ctx)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return nil, nil, err
+	}
+
+	var newest *entities.SigningKey
+	if len(keys) > 0 {
+		newest = keys[len(keys)-1]
+	}
+
+	if newest == nil || !now.Before(newest.CreatedAt.Add(r.rotationPeriod)) {
+		// Without any key there is nothing to sign with, so the first key is active at once
+		activatesAt := now
+		if newest != nil {
+			activatesAt = now.Add(r.propagationDelay)
+
+			// Other instances sign with the replaced key until their next check, verifiers accept its tokens for the leeway longer
+			newest.RetiresAt = activatesAt.Add(r.checkInterval + r.maxTokenLifetime + r.leeway)
+			err = signingKeyRepository.Update(ctx, newest)
+			if err != nil {
+				_ = unitOfWork.Rollback(ctx)
+
+				return nil, nil, err
+			}
+		}
+
+		key, err := r.generate(now, activatesAt)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, nil, err
+		}
+
+		err = signingKeyRepository.Create(ctx, key)
+		if err != nil {
+			_ = unitOfWork.Rollback(ctx)
+
+			return nil, nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.decrypt(keys, now)
+}
+
+func (r *JwtKeyRotator) generate(now, activatesAt time.Time) (*entities.SigningKey, error) {
+	var keyMaterial []byte
+	var err error
+	switch r.algorithm {
+	case jwt.SigningMethodHS512.Alg():
+		keyMaterial = make([]byte, hmacSecretLength)
+		_, err = rand.Read(keyMaterial)
+	default:
+		var privateKey crypto.Signer
+		switch r.algorithm {
+		case jwt.SigningMethodES256.Alg():
+			privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		case jwt.SigningMethodRS256.Alg():
+			privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		default:
+			_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keyMaterial, err = x509.MarshalPKCS8PrivateKey(privateKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	id := r.uuidProvider.Random().String()
+
+	encryptedKey, err := r.seal(id, keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewSigningKey(id, r.algorithm, encryptedKey, now, activatesAt, time.Time{}), nil
+}
+
+// decrypt returns the newest key that has been activated as the active key and all others as verify-only keys.
+func (r *JwtKeyRotator) decrypt(keys []*entities.SigningKey, now time.Time) (*JwtKey, []*JwtKey, error) {
+	var activeKey *JwtKey
+	verificationKeys := make([]*JwtKey, 0, len(keys))
+	for _, key := range keys {
+		keyMaterial, err := r.open(key.Id, key.EncryptedKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("JWT key %q: %w", key.Id, err)
+		}
+
+		var jwtKey *JwtKey
+		if key.Algorithm == jwt.SigningMethodHS512.Alg() {
+			jwtKey = NewHmacJwtKey(key.Id, keyMaterial, key.RetiresAt)
+		} else {
+			keyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyMaterial})
+
+			jwtKey, err = NewAsymmetricJwtKey(key.Id, key.Algorithm, keyPem, key.RetiresAt)
+			if err != nil {
+				return nil, nil, fmt.Errorf("JWT key %q: %w", key.Id, err)
+			}
+		}
+
+		if key.ActivatesAt.After(now) {
+			verificationKeys = append(verificationKeys, jwtKey)
+			continue
+		}
+		if activeKey != nil {
+			verificationKeys = append(verificationKeys, activeKey)
+		}
+		activeKey = jwtKey
+	}
+
+	if activeKey == nil {
+		return nil, nil, errors.New("no JWT key is active")
+	}
+
+	return activeKey, verificationKeys, nil
+}
+
+// seal binds the ciphertext to the key id, so encrypted keys cannot be swapped between rows.
+func (r *JwtKeyRotator) seal(id string, keyMaterial []byte) ([]byte, error) {
+	nonce := make([]byte, r.masterKey.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.masterKey.Seal(nonce, nonce, keyMaterial, []byte(id)), nil
+}
+
+func (r *JwtKeyRotator) open(id string, encryptedKey []byte) ([]byte, error) {
+	nonceSize := r.masterKey.NonceSize()
+	if len(encryptedKey) < nonceSize {
+		return nil, errors.New("encrypted key is too short")
+	}
+
+	return r.masterKey.Open(nil, encryptedKey[:nonceSize], encryptedKey[nonceSize:], []byte(id))
+}
@@ -0,0 +1,220 @@
+package infrastructure_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/entities"
+	"grpc-auth/internal/core/value-objects"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+	"time"
+)
+
+const (
+	rotatorMasterKey        = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	rotatorRotationPeriod   = 720 * time.Hour
+	rotatorPropagationDelay = time.Hour
+	rotatorMaxTokenLifetime = 15 * time.Minute
+	rotatorLeeway           = 30 * time.Second
+	rotatorCheckInterval    = time.Minute
+)
+
+func TestRotate_EmptyStore(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	keyUuid, _ := uuid.Parse("b4c7c3c6-4a4b-4bc4-9a57-5a4d8c0b2f11")
+
+	signingKeyRepository := infrastructure.NewMockSigningKeyRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	rotator, _ := infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SigningKeyRepository").Return(signingKeyRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	timeProvider.On("Now").Return(now)
+	uuidProvider.On("Random").Return(keyUuid)
+	signingKeyRepository.On("Lock", ctx).Return(nil)
+	signingKeyRepository.On("DeleteRetired", ctx, now).Return(nil)
+	signingKeyRepository.On("GetAll", ctx).Return([]*entities.SigningKey{}, nil)
+	signingKeyRepository.On("Create", ctx, mock.MatchedBy(func(key *entities.SigningKey) bool {
+		return key.Id == keyUuid.String() && key.Algorithm == "ES256" && key.ActivatesAt.Equal(now) && key.RetiresAt.IsZero()
+	})).Return(nil)
+
+	// Act
+	activeKey, verificationKeys, err := rotator.Rotate(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, keyUuid.String(), activeKey.Id)
+	assert.Empty(t, verificationKeys)
+	signingKeyRepository.AssertExpectations(t)
+	unitOfWork.AssertExpectations(t)
+
+	manager, err := infrastructure.NewKeyRingJwtManager(activeKey, verificationKeys, jwtClaimsPolicy, timeProvider)
+	assert.NoError(t, err)
+	info := &value_objects.AuthInfo{UserUuid: keyUuid, ExpirationAt: now.Add(time.Hour), IssuedAt: now}
+	token, err := manager.Generate(info)
+	assert.NoError(t, err)
+	actualInfo, err := manager.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, info, actualInfo)
+}
+
+func TestRotate_KeyIsYoung(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	now := createdAt.Add(rotatorRotationPeriod - time.Second)
+	storedKey := createStoredKey(t, createdAt)
+
+	signingKeyRepository := infrastructure.NewMockSigningKeyRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	rotator, _ := infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SigningKeyRepository").Return(signingKeyRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	timeProvider.On("Now").Return(now)
+	signingKeyRepository.On("Lock", ctx).Return(nil)
+	signingKeyRepository.On("DeleteRetired", ctx, now).Return(nil)
+	signingKeyRepository.On("GetAll", ctx).Return([]*entities.SigningKey{storedKey}, nil)
+
+	// Act
+	activeKey, verificationKeys, err := rotator.Rotate(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, storedKey.Id, activeKey.Id)
+	assert.Empty(t, verificationKeys)
+	signingKeyRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	signingKeyRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRotate_KeyIsOld(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	now := createdAt.Add(rotatorRotationPeriod)
+	storedKey := createStoredKey(t, createdAt)
+	keyUuid, _ := uuid.Parse("0e3d0f2a-6f0c-4f59-8a36-27c1a8e4d5b9")
+
+	signingKeyRepository := infrastructure.NewMockSigningKeyRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	rotator, _ := infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SigningKeyRepository").Return(signingKeyRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	timeProvider.On("Now").Return(now)
+	uuidProvider.On("Random").Return(keyUuid)
+	signingKeyRepository.On("Lock", ctx).Return(nil)
+	signingKeyRepository.On("DeleteRetired", ctx, now).Return(nil)
+	signingKeyRepository.On("GetAll", ctx).Return([]*entities.SigningKey{storedKey}, nil)
+	signingKeyRepository.On("Update", ctx, mock.MatchedBy(func(key *entities.SigningKey) bool {
+		return key.Id == storedKey.Id && key.RetiresAt.Equal(now.Add(rotatorPropagationDelay+rotatorCheckInterval+rotatorMaxTokenLifetime+rotatorLeeway))
+	})).Return(nil)
+	signingKeyRepository.On("Create", ctx, mock.MatchedBy(func(key *entities.SigningKey) bool {
+		return key.Id == keyUuid.String() && key.ActivatesAt.Equal(now.Add(rotatorPropagationDelay))
+	})).Return(nil)
+
+	// Act
+	activeKey, verificationKeys, err := rotator.Rotate(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, storedKey.Id, activeKey.Id)
+	assert.Len(t, verificationKeys, 1)
+	assert.Equal(t, keyUuid.String(), verificationKeys[0].Id)
+	signingKeyRepository.AssertExpectations(t)
+}
+
+func TestRotate_WrongMasterKey(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	createdAt := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	storedKey := createStoredKey(t, createdAt)
+
+	signingKeyRepository := infrastructure.NewMockSigningKeyRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	rotator, _ := infrastructure.NewJwtKeyRotator("ES256", "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=", rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SigningKeyRepository").Return(signingKeyRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	timeProvider.On("Now").Return(createdAt)
+	signingKeyRepository.On("Lock", ctx).Return(nil)
+	signingKeyRepository.On("DeleteRetired", ctx, createdAt).Return(nil)
+	signingKeyRepository.On("GetAll", ctx).Return([]*entities.SigningKey{storedKey}, nil)
+
+	// Act
+	_, _, err := rotator.Rotate(ctx)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestNewJwtKeyRotator_InvalidConfiguration(t *testing.T) {
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+
+	_, err := infrastructure.NewJwtKeyRotator("HS256", rotatorMasterKey, rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+	assert.Error(t, err)
+
+	_, err = infrastructure.NewJwtKeyRotator("ES256", "c2hvcnQ=", rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+	assert.Error(t, err)
+
+	_, err = infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorPropagationDelay, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+	assert.Error(t, err)
+
+	_, err = infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorRotationPeriod, rotatorCheckInterval, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+	assert.Error(t, err)
+}
+
+// createStoredKey rotates an empty store to obtain a key encrypted with the test master key.
+func createStoredKey(t *testing.T, createdAt time.Time) *entities.SigningKey {
+	ctx := context.Background()
+	keyUuid, _ := uuid.Parse("7d2f5c8e-1b3a-4c6d-9e0f-a1b2c3d4e5f6")
+
+	signingKeyRepository := infrastructure.NewMockSigningKeyRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+	uuidProvider := infrastructure.NewMockUuidProvider()
+	rotator, _ := infrastructure.NewJwtKeyRotator("ES256", rotatorMasterKey, rotatorRotationPeriod, rotatorPropagationDelay, rotatorMaxTokenLifetime, rotatorLeeway, rotatorCheckInterval, unitOfWorkStarter, timeProvider, uuidProvider)
+
+	var storedKey *entities.SigningKey
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("SigningKeyRepository").Return(signingKeyRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	timeProvider.On("Now").Return(createdAt)
+	uuidProvider.On("Random").Return(keyUuid)
+	signingKeyRepository.On("Lock", ctx).Return(nil)
+	signingKeyRepository.On("DeleteRetired", ctx, createdAt).Return(nil)
+	signingKeyRepository.On("GetAll", ctx).Return([]*entities.SigningKey{}, nil)
+	signingKeyRepository.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		storedKey = args.Get(1).(*entities.SigningKey)
+	}).Return(nil)
+
+	_, _, err := rotator.Rotate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return storedKey
+}
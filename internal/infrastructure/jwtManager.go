@@ -1,31 +1,147 @@
 package infrastructure
 
 import (
+	"errors"
+	"expvar"
+	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/services"
 	"grpc-auth/internal/core/value-objects"
+	"sort"
+	"sync"
 	"time"
 )
 
+var jwtParseFailures = expvar.NewMap("jwt_parse_failures")
+
+// JwtClaimsPolicy configures the registered claims written into tokens and required when they are parsed.
+// An empty issuer or audience is neither written nor checked.
+type JwtClaimsPolicy struct {
+	Issuer            string
+	Audience          string
+	Leeway            time.Duration // Tolerated clock skew between the issuer and the verifiers
+	LegacyTokensUntil time.Time     // Tokens with the former userUuid and expirationAt claims are accepted before this moment
+}
+
+// jwtClaims are the RFC 7519 registered claims, the subject is the user UUID.
+// The sid claim of OpenID Connect is the UUID of the session the token was issued for.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Scope     string `json:"scope,omitempty"`
+	SessionId string `json:"sid,omitempty"`
+}
+
+// RealJwtManager signs with the active key of its key ring and verifies with any key of the ring that is not retired,
+// so signing keys can be rotated without invalidating tokens that are still in use.
 type RealJwtManager struct {
-	key []byte
+	mutex        sync.RWMutex
+	activeKey    *JwtKey
+	keys         map[string]*JwtKey
+	claimsPolicy JwtClaimsPolicy
+	validator    *jwt.Validator
+	timeProvider services.TimeProvider
+}
+
+func NewRealJwtManager(key []byte, claimsPolicy JwtClaimsPolicy, timeProvider services.TimeProvider) *RealJwtManager {
+	jwtManager, _ := NewKeyRingJwtManager(NewHmacJwtKey("", key, time.Time{}), nil, claimsPolicy, timeProvider)
+
+	return jwtManager
 }
 
-func NewRealJwtManager(key []byte) *RealJwtManager {
-	return &RealJwtManager{key}
+// NewAsymmetricJwtManager signs with a PEM-encoded private key, so tokens can be verified with the public key only.
+// Supported algorithms are ES256, RS256 and EdDSA.
+func NewAsymmetricJwtManager(algorithm string, privateKeyPem []byte, claimsPolicy JwtClaimsPolicy, timeProvider services.TimeProvider) (*RealJwtManager, error) {
+	key, err := NewAsymmetricJwtKey("", algorithm, privateKeyPem, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyRingJwtManager(key, nil, claimsPolicy, timeProvider)
+}
+
+// NewKeyRingJwtManager identifies keys by the kid header. Tokens without it are verified with the key with the empty id,
+// which is the id of keys configured before the key ring existed.
+func NewKeyRingJwtManager(activeKey *JwtKey, verificationKeys []*JwtKey, claimsPolicy JwtClaimsPolicy, timeProvider services.TimeProvider) (*RealJwtManager, error) {
+	options := []jwt.ParserOption{jwt.WithLeeway(claimsPolicy.Leeway), jwt.WithTimeFunc(timeProvider.Now), jwt.WithExpirationRequired(), jwt.WithIssuedAt()}
+	if claimsPolicy.Issuer != "" {
+		options = append(options, jwt.WithIssuer(claimsPolicy.Issuer))
+	}
+	if claimsPolicy.Audience != "" {
+		options = append(options, jwt.WithAudience(claimsPolicy.Audience))
+	}
+
+	jwtManager := &RealJwtManager{claimsPolicy: claimsPolicy, validator: jwt.NewValidator(options...), timeProvider: timeProvider}
+
+	err := jwtManager.SetKeys(activeKey, verificationKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwtManager, nil
+}
+
+// SetKeys replaces the key ring, it is safe to call while tokens are generated and parsed.
+func (jm *RealJwtManager) SetKeys(activeKey *JwtKey, verificationKeys []*JwtKey) error {
+	if activeKey.SigningKey == nil {
+		return fmt.Errorf("active JWT key %q has no private key", activeKey.Id)
+	}
+	// Tokens signed with a retired key would be rejected at once. Keys that never retire need no clock
+	if !activeKey.RetiresAt.IsZero() && activeKey.IsRetired(jm.timeProvider.Now()) {
+		return fmt.Errorf("active JWT key %q is already retired", activeKey.Id)
+	}
+
+	keys := map[string]*JwtKey{activeKey.Id: activeKey}
+	for _, key := range verificationKeys {
+		if _, ok := keys[key.Id]; ok {
+			return fmt.Errorf("JWT key id %q is not unique", key.Id)
+		}
+		keys[key.Id] = key
+	}
+
+	jm.mutex.Lock()
+	defer jm.mutex.Unlock()
+
+	jm.activeKey = activeKey
+	jm.keys = keys
+
+	return nil
 }
 
 func (jm *RealJwtManager) Generate(info *value_objects.AuthInfo) (string, error) {
-	token := jwt.NewWithClaims(
-		jwt.SigningMethodHS512,
-		jwt.MapClaims{
-			"userUuid":     info.UserUuid,
-			"expirationAt": info.ExpirationAt,
+	jm.mutex.RLock()
+	activeKey := jm.activeKey
+	jm.mutex.RUnlock()
+
+	now := jm.timeProvider.Now()
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jm.claimsPolicy.Issuer,
+			Subject:   info.UserUuid.String(),
+			ExpiresAt: jwt.NewNumericDate(info.ExpirationAt),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
-	)
+		Scope: info.Scope,
+	}
+	if jm.claimsPolicy.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{jm.claimsPolicy.Audience}
+	}
+	if info.TokenUuid != uuid.Nil {
+		claims.ID = info.TokenUuid.String()
+	}
+	if info.SessionUuid != uuid.Nil {
+		claims.SessionId = info.SessionUuid.String()
+	}
 
-	signedToken, err := token.SignedString(jm.key)
+	token := jwt.NewWithClaims(activeKey.Method, claims)
+
+	if activeKey.Id != "" {
+		token.Header["kid"] = activeKey.Id
+	}
+
+	signedToken, err := token.SignedString(activeKey.SigningKey)
 	if err != nil {
 		return "", err
 	}
@@ -33,43 +149,205 @@ func (jm *RealJwtManager) Generate(info *value_objects.AuthInfo) (string, error)
 	return signedToken, nil
 }
 
-func (jm *RealJwtManager) Parse(tokenString string) *value_objects.AuthInfo {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return jm.key, nil
-	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}))
-	if err != nil || !token.Valid {
-		return nil
+// Parse accepts tokens signed by a known key and valid at the current time. Rejections are counted by reason
+// in the jwt_parse_failures expvar map.
+func (jm *RealJwtManager) Parse(tokenString string) (*value_objects.AuthInfo, error) {
+	info, err := jm.parse(tokenString)
+	if err != nil {
+		jwtParseFailures.Add(err.Reason, 1)
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (jm *RealJwtManager) parse(tokenString string) (*value_objects.AuthInfo, *services.TokenError) {
+	// Claims are validated below, once the format of the token is known
+	token, err := jwt.Parse(tokenString, jm.verificationKey, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, tokenError(err)
 	}
 
 	claims := token.Claims.(jwt.MapClaims)
 
-	userUuidAny, ok := claims["userUuid"]
+	if _, ok := claims["userUuid"]; ok {
+		return jm.parseLegacyClaims(claims)
+	}
+
+	err = jm.validator.Validate(claims)
+	if err != nil {
+		return nil, tokenError(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token subject is invalid"}
+	}
+	userUuid, err := uuid.Parse(subject)
+	if err != nil {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token subject is invalid"}
+	}
+
+	expirationAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token expiration time is invalid"}
+	}
+
+	// The validator has already checked the type of iat, so only a missing claim is left
+	var issuedAt time.Time
+	if issuedAtDate, _ := claims.GetIssuedAt(); issuedAtDate != nil {
+		issuedAt = issuedAtDate.UTC()
+	}
+
+	scope, ok := parseScope(claims)
 	if !ok {
-		return nil
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token scope is invalid"}
 	}
-	userUuidString, ok := userUuidAny.(string)
+
+	tokenUuid, ok := parseUuidClaim(claims, "jti")
 	if !ok {
-		return nil
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token id is invalid"}
 	}
-	userUuid, err := uuid.Parse(userUuidString)
-	if err != nil {
-		return nil
+
+	sessionUuid, ok := parseUuidClaim(claims, "sid")
+	if !ok {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token session id is invalid"}
+	}
+
+	return &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt.UTC(), Scope: scope, TokenUuid: tokenUuid, SessionUuid: sessionUuid, IssuedAt: issuedAt}, nil
+}
+
+// parseLegacyClaims accepts tokens with the userUuid and expirationAt claims used before the registered claims,
+// until the transition window of the claims policy ends.
+func (jm *RealJwtManager) parseLegacyClaims(claims jwt.MapClaims) (*value_objects.AuthInfo, *services.TokenError) {
+	now := jm.timeProvider.Now()
+	if !now.Before(jm.claimsPolicy.LegacyTokensUntil) {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token format is no longer accepted"}
 	}
 
-	expirationAtAny, ok := claims["expirationAt"]
+	userUuidString, ok := claims["userUuid"].(string)
 	if !ok {
-		return nil
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token subject is invalid"}
 	}
-	expirationAtString, ok := expirationAtAny.(string)
+	userUuid, err := uuid.Parse(userUuidString)
+	if err != nil {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token subject is invalid"}
+	}
+
+	expirationAtString, ok := claims["expirationAt"].(string)
 	if !ok {
-		return nil
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token expiration time is invalid"}
 	}
 	expirationAt, err := time.Parse(time.RFC3339, expirationAtString)
 	if err != nil {
-		return nil
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token expiration time is invalid"}
+	}
+	if !now.Before(expirationAt.Add(jm.claimsPolicy.Leeway)) {
+		return nil, &services.TokenError{Reason: services.TokenExpired, Message: "token is expired"}
+	}
+
+	scope, ok := parseScope(claims)
+	if !ok {
+		return nil, &services.TokenError{Reason: services.TokenClaimsInvalid, Message: "token scope is invalid"}
+	}
+
+	return &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, Scope: scope}, nil
+}
+
+// tokenError classifies the errors of the jwt library. Errors of the key function are already classified.
+func tokenError(err error) *services.TokenError {
+	var keyError *services.TokenError
+	switch {
+	case errors.As(err, &keyError):
+		return keyError
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return &services.TokenError{Reason: services.TokenMalformed, Message: "token is malformed"}
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return &services.TokenError{Reason: services.TokenSignatureInvalid, Message: "token signature is invalid"}
+	case errors.Is(err, jwt.ErrTokenUnverifiable):
+		return &services.TokenError{Reason: services.TokenAlgorithmInvalid, Message: "token signing method is not supported"}
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return &services.TokenError{Reason: services.TokenExpired, Message: "token is expired"}
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return &services.TokenError{Reason: services.TokenNotYetValid, Message: "token is not valid yet"}
+	default:
+		return &services.TokenError{Reason: services.TokenClaimsInvalid, Message: fmt.Sprintf("token claims are invalid: %v", err)}
+	}
+}
+
+// parseScope treats a missing scope claim as an unrestricted token.
+func parseScope(claims jwt.MapClaims) (string, bool) {
+	scopeAny, ok := claims["scope"]
+	if !ok {
+		return "", true
+	}
+	scope, ok := scopeAny.(string)
+
+	return scope, ok
+}
+
+// parseUuidClaim treats a missing claim as the nil UUID. Tokens without jti cannot be revoked
+// and tokens without sid are not bound to a session.
+func parseUuidClaim(claims jwt.MapClaims, name string) (uuid.UUID, bool) {
+	idAny, ok := claims[name]
+	if !ok {
+		return uuid.Nil, true
+	}
+	id, ok := idAny.(string)
+	if !ok {
+		return uuid.Nil, false
 	}
+	idUuid, err := uuid.Parse(id)
 
-	return &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt}
+	return idUuid, err == nil
+}
+
+func (jm *RealJwtManager) PublicKeys() []value_objects.Jwk {
+	now := jm.timeProvider.Now()
+
+	jm.mutex.RLock()
+	defer jm.mutex.RUnlock()
+
+	jwks := make([]value_objects.Jwk, 0, len(jm.keys))
+	for _, key := range jm.keys {
+		if key.IsRetired(now) {
+			continue
+		}
+		if jwk, ok := key.Jwk(); ok {
+			jwks = append(jwks, jwk)
+		}
+	}
+	// Map order is random, a stable order keeps the document cacheable
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].KeyId < jwks[j].KeyId })
+
+	return jwks
+}
+
+func (jm *RealJwtManager) verificationKey(token *jwt.Token) (any, error) {
+	keyId := ""
+	if kid, ok := token.Header["kid"]; ok {
+		keyId, ok = kid.(string)
+		if !ok {
+			return nil, &services.TokenError{Reason: services.TokenMalformed, Message: "token kid header is not a string"}
+		}
+	}
+
+	jm.mutex.RLock()
+	key, ok := jm.keys[keyId]
+	jm.mutex.RUnlock()
+	if !ok {
+		return nil, &services.TokenError{Reason: services.TokenKeyUnknown, Message: fmt.Sprintf("token key %q is unknown", keyId)}
+	}
+	if key.IsRetired(jm.timeProvider.Now()) {
+		return nil, &services.TokenError{Reason: services.TokenKeyUnknown, Message: fmt.Sprintf("token key %q is retired", keyId)}
+	}
+	// Each key is bound to its algorithm, so a token cannot pick a weaker one
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, &services.TokenError{Reason: services.TokenAlgorithmInvalid, Message: fmt.Sprintf("token key %q does not use %s", keyId, token.Method.Alg())}
+	}
+
+	return key.VerificationKey, nil
 }
 
 type MockJwtManager struct {
@@ -85,7 +363,12 @@ func (jm *MockJwtManager) Generate(info *value_objects.AuthInfo) (string, error)
 	return args.String(0), args.Error(1)
 }
 
-func (jm *MockJwtManager) Parse(tokenString string) *value_objects.AuthInfo {
+func (jm *MockJwtManager) Parse(tokenString string) (*value_objects.AuthInfo, error) {
 	args := jm.Called(tokenString)
-	return args.Get(0).(*value_objects.AuthInfo)
+	return args.Get(0).(*value_objects.AuthInfo), args.Error(1)
+}
+
+func (jm *MockJwtManager) PublicKeys() []value_objects.Jwk {
+	args := jm.Called()
+	return args.Get(0).([]value_objects.Jwk)
 }
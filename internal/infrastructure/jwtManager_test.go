@@ -1,21 +1,49 @@
 package infrastructure_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/core/services"
 	"grpc-auth/internal/core/value-objects"
 	"grpc-auth/internal/infrastructure"
 	"testing"
 	"time"
 )
 
+var jwtClaimsPolicy = infrastructure.JwtClaimsPolicy{Issuer: "grpc-auth", Audience: "grpc-auth", Leeway: time.Minute}
+
+// newJwtTimeProvider returns a clock shortly before the expiration of the tokens in the tests.
+func newJwtTimeProvider() *infrastructure.MockTimeProvider {
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(time.Date(1986, time.April, 26, 1, 0, 0, 0, time.UTC))
+
+	return timeProvider
+}
+
+func assertTokenError(t *testing.T, expectedReason string, err error) {
+	var tokenError *services.TokenError
+	if assert.ErrorAs(t, err, &tokenError) {
+		assert.Equal(t, expectedReason, tokenError.Reason)
+	}
+}
+
 func TestGenerate(t *testing.T) {
 	// Arrange
 	key := []byte("123_secret_321")
 	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
 	expirationAt := time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)
 	info := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt}
-	manager := infrastructure.NewRealJwtManager(key)
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
 
 	// Act
 	token, err := manager.Generate(info)
@@ -31,15 +59,18 @@ func TestParse(t *testing.T) {
 	// Arrange
 	key := []byte("123_secret_321")
 	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	tokenUuid, _ := uuid.Parse("3f2b8c1d-5e6a-4b7c-8d9e-0f1a2b3c4d5e")
+	sessionUuid, _ := uuid.Parse("7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d")
 	expirationAt := time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)
-	expectedInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt}
-	manager := infrastructure.NewRealJwtManager(key)
+	expectedInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, TokenUuid: tokenUuid, SessionUuid: sessionUuid, IssuedAt: time.Date(1986, time.April, 26, 1, 0, 0, 0, time.UTC)}
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
 	token, _ := manager.Generate(expectedInfo)
 
 	// Act
-	actualInfo := manager.Parse(token)
+	actualInfo, err := manager.Parse(token)
 
 	// Assert
+	assert.NoError(t, err)
 	assert.NotEmpty(t, actualInfo)
 
 	t.Log("expected info: ", expectedInfo)
@@ -47,3 +78,308 @@ func TestParse(t *testing.T) {
 
 	assert.Equal(t, *expectedInfo, *actualInfo)
 }
+
+func TestParse_RestrictedToken(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	expirationAt := time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)
+	expectedInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, Scope: value_objects.PasswordChangeScope, IssuedAt: time.Date(1986, time.April, 26, 1, 0, 0, 0, time.UTC)}
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
+	token, _ := manager.Generate(expectedInfo)
+
+	// Act
+	actualInfo, err := manager.Parse(token)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actualInfo)
+	assert.Equal(t, *expectedInfo, *actualInfo)
+}
+
+func TestParse_AsymmetricAlgorithms(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	for algorithm, privateKey := range map[string]crypto.Signer{"ES256": ecKey, "RS256": rsaKey, "EdDSA": edKey} {
+		t.Run(algorithm, func(t *testing.T) {
+			// Arrange
+			der, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+			privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+			userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+			expirationAt := time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)
+			expectedInfo := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, IssuedAt: time.Date(1986, time.April, 26, 1, 0, 0, 0, time.UTC)}
+			manager, err := infrastructure.NewAsymmetricJwtManager(algorithm, privateKeyPem, jwtClaimsPolicy, newJwtTimeProvider())
+			assert.NoError(t, err)
+			token, _ := manager.Generate(expectedInfo)
+
+			// Act
+			actualInfo, err := manager.Parse(token)
+			_, publicKeyErr := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+				return privateKey.Public(), nil
+			}, jwt.WithValidMethods([]string{algorithm}), jwt.WithoutClaimsValidation())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NotEmpty(t, actualInfo)
+			assert.Equal(t, *expectedInfo, *actualInfo)
+			assert.NoError(t, publicKeyErr)
+		})
+	}
+}
+
+func TestParse_AlgorithmMismatch(t *testing.T) {
+	// Arrange
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	manager, _ := infrastructure.NewAsymmetricJwtManager("ES256", privateKeyPem, jwtClaimsPolicy, newJwtTimeProvider())
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: time.Now()}
+	token, _ := infrastructure.NewRealJwtManager([]byte("123_secret_321"), jwtClaimsPolicy, newJwtTimeProvider()).Generate(info)
+
+	// Act
+	actualInfo, err := manager.Parse(token)
+
+	// Assert
+	assert.Nil(t, actualInfo)
+	assertTokenError(t, services.TokenAlgorithmInvalid, err)
+}
+
+func TestNewAsymmetricJwtManager_UnsupportedAlgorithm(t *testing.T) {
+	// Act
+	_, err := infrastructure.NewAsymmetricJwtManager("none", nil, jwtClaimsPolicy, newJwtTimeProvider())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestParse_RotatedKeyRing(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(now)
+	oldKey := infrastructure.NewHmacJwtKey("old", []byte("old_secret"), now.Add(time.Hour))
+	retiredKey := infrastructure.NewHmacJwtKey("retired", []byte("retired_secret"), now)
+	newKey := infrastructure.NewHmacJwtKey("new", []byte("new_secret"), time.Time{})
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: now.Add(time.Hour), IssuedAt: now}
+
+	oldManager, _ := infrastructure.NewKeyRingJwtManager(oldKey, nil, jwtClaimsPolicy, timeProvider)
+	retiredManager, _ := infrastructure.NewKeyRingJwtManager(infrastructure.NewHmacJwtKey("retired", []byte("retired_secret"), time.Time{}), nil, jwtClaimsPolicy, timeProvider)
+	unknownManager, _ := infrastructure.NewKeyRingJwtManager(infrastructure.NewHmacJwtKey("unknown", []byte("old_secret"), time.Time{}), nil, jwtClaimsPolicy, timeProvider)
+	manager, err := infrastructure.NewKeyRingJwtManager(newKey, []*infrastructure.JwtKey{oldKey, retiredKey}, jwtClaimsPolicy, timeProvider)
+	assert.NoError(t, err)
+
+	newToken, _ := manager.Generate(info)
+	oldToken, _ := oldManager.Generate(info)
+	retiredToken, _ := retiredManager.Generate(info)
+	unknownToken, _ := unknownManager.Generate(info)
+
+	// Act
+	newInfo, newErr := manager.Parse(newToken)
+	oldInfo, oldErr := manager.Parse(oldToken)
+	retiredInfo, retiredErr := manager.Parse(retiredToken)
+	unknownInfo, unknownErr := manager.Parse(unknownToken)
+
+	// Assert
+	assert.NoError(t, newErr)
+	assert.Equal(t, info, newInfo)
+	assert.NoError(t, oldErr)
+	assert.Equal(t, info, oldInfo)
+	assert.Nil(t, retiredInfo)
+	assertTokenError(t, services.TokenKeyUnknown, retiredErr)
+	assert.Nil(t, unknownInfo)
+	assertTokenError(t, services.TokenKeyUnknown, unknownErr)
+}
+
+func TestNewKeyRingJwtManager_ActiveKeyIsRetired(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(now)
+	retiredKey := infrastructure.NewHmacJwtKey("retired", []byte("retired_secret"), now.Add(-time.Second))
+
+	// Act
+	_, err := infrastructure.NewKeyRingJwtManager(retiredKey, nil, jwtClaimsPolicy, timeProvider)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestParse_TokenWithoutKeyId(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC))
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: time.Date(2025, 4, 8, 15, 39, 0, 0, time.UTC), IssuedAt: time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)}
+	legacyToken, _ := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, timeProvider).Generate(info)
+	manager, _ := infrastructure.NewKeyRingJwtManager(
+		infrastructure.NewHmacJwtKey("new", []byte("new_secret"), time.Time{}),
+		[]*infrastructure.JwtKey{infrastructure.NewHmacJwtKey("", key, time.Time{})},
+		jwtClaimsPolicy,
+		timeProvider,
+	)
+
+	// Act
+	actualInfo, err := manager.Parse(legacyToken)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, info, actualInfo)
+}
+
+func TestPublicKeys(t *testing.T) {
+	// Arrange
+	now := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(now)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	newKey := newAsymmetricJwtKey(t, "new", "ES256", ecKey, time.Time{})
+	oldKey := newAsymmetricJwtKey(t, "old", "RS256", rsaKey, now.Add(time.Hour))
+	retiredKey := newAsymmetricJwtKey(t, "retired", "EdDSA", edKey, now)
+	hmacKey := infrastructure.NewHmacJwtKey("hmac", []byte("123_secret_321"), time.Time{})
+	manager, _ := infrastructure.NewKeyRingJwtManager(newKey, []*infrastructure.JwtKey{oldKey, retiredKey, hmacKey}, jwtClaimsPolicy, timeProvider)
+
+	// Act
+	keys := manager.PublicKeys()
+
+	// Assert
+	encode := base64.RawURLEncoding.EncodeToString
+	assert.Equal(t, []value_objects.Jwk{
+		{KeyType: "EC", KeyId: "new", Use: "sig", Algorithm: "ES256", Curve: "P-256", X: encode(ecKey.X.FillBytes(make([]byte, 32))), Y: encode(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{KeyType: "RSA", KeyId: "old", Use: "sig", Algorithm: "RS256", N: encode(rsaKey.N.Bytes()), E: "AQAB"},
+	}, keys)
+}
+
+func newAsymmetricJwtKey(t *testing.T, id, algorithm string, privateKey crypto.Signer, retiresAt time.Time) *infrastructure.JwtKey {
+	der, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	key, err := infrastructure.NewAsymmetricJwtKey(id, algorithm, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), retiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestGenerate_RegisteredClaims(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	userUuid, _ := uuid.Parse("e631182f-2be6-4b24-84a9-339881d1c89b")
+	expirationAt := time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)
+	tokenUuid, _ := uuid.Parse("3f2b8c1d-5e6a-4b7c-8d9e-0f1a2b3c4d5e")
+	sessionUuid, _ := uuid.Parse("7a8b9c0d-1e2f-4a3b-9c4d-5e6f7a8b9c0d")
+	info := &value_objects.AuthInfo{UserUuid: userUuid, ExpirationAt: expirationAt, TokenUuid: tokenUuid, SessionUuid: sessionUuid}
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
+
+	// Act
+	token, _ := manager.Generate(info)
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		return key, nil
+	}, jwt.WithoutClaimsValidation())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, userUuid.String(), claims["sub"])
+	assert.Equal(t, "grpc-auth", claims["iss"])
+	assert.Equal(t, []any{"grpc-auth"}, claims["aud"])
+	assert.Equal(t, float64(expirationAt.Unix()), claims["exp"])
+	assert.Equal(t, float64(time.Date(1986, time.April, 26, 1, 0, 0, 0, time.UTC).Unix()), claims["iat"])
+	assert.Equal(t, claims["iat"], claims["nbf"])
+	assert.Equal(t, tokenUuid.String(), claims["jti"])
+	assert.Equal(t, sessionUuid.String(), claims["sid"])
+	assert.NotContains(t, claims, "userUuid")
+}
+
+func TestParse_ExpiredToken(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	issuedAt := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	expirationAt := issuedAt.Add(time.Hour)
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: expirationAt, IssuedAt: issuedAt}
+	issuerTimeProvider := infrastructure.NewMockTimeProvider()
+	issuerTimeProvider.On("Now").Return(issuedAt)
+	token, _ := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, issuerTimeProvider).Generate(info)
+	withinLeewayTimeProvider := infrastructure.NewMockTimeProvider()
+	withinLeewayTimeProvider.On("Now").Return(expirationAt.Add(jwtClaimsPolicy.Leeway - time.Second))
+	afterLeewayTimeProvider := infrastructure.NewMockTimeProvider()
+	afterLeewayTimeProvider.On("Now").Return(expirationAt.Add(jwtClaimsPolicy.Leeway + time.Second))
+
+	// Act
+	withinLeewayInfo, withinLeewayErr := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, withinLeewayTimeProvider).Parse(token)
+	afterLeewayInfo, afterLeewayErr := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, afterLeewayTimeProvider).Parse(token)
+
+	// Assert
+	assert.NoError(t, withinLeewayErr)
+	assert.Equal(t, info, withinLeewayInfo)
+	assert.Nil(t, afterLeewayInfo)
+	assertTokenError(t, services.TokenExpired, afterLeewayErr)
+}
+
+func TestParse_WrongIssuerOrAudience(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)}
+	otherIssuerPolicy := infrastructure.JwtClaimsPolicy{Issuer: "other", Audience: jwtClaimsPolicy.Audience}
+	otherAudiencePolicy := infrastructure.JwtClaimsPolicy{Issuer: jwtClaimsPolicy.Issuer, Audience: "other"}
+	otherIssuerToken, _ := infrastructure.NewRealJwtManager(key, otherIssuerPolicy, newJwtTimeProvider()).Generate(info)
+	otherAudienceToken, _ := infrastructure.NewRealJwtManager(key, otherAudiencePolicy, newJwtTimeProvider()).Generate(info)
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
+
+	// Act
+	otherIssuerInfo, otherIssuerErr := manager.Parse(otherIssuerToken)
+	otherAudienceInfo, otherAudienceErr := manager.Parse(otherAudienceToken)
+
+	// Assert
+	assert.Nil(t, otherIssuerInfo)
+	assertTokenError(t, services.TokenClaimsInvalid, otherIssuerErr)
+	assert.Nil(t, otherAudienceInfo)
+	assertTokenError(t, services.TokenClaimsInvalid, otherAudienceErr)
+}
+
+func TestParse_LegacyClaims(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	now := time.Date(2025, 4, 8, 14, 39, 0, 0, time.UTC)
+	timeProvider := infrastructure.NewMockTimeProvider()
+	timeProvider.On("Now").Return(now)
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: now.Add(time.Hour)}
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{
+		"userUuid":     info.UserUuid,
+		"expirationAt": info.ExpirationAt,
+		"scope":        "",
+	}).SignedString(key)
+	transitionPolicy := jwtClaimsPolicy
+	transitionPolicy.LegacyTokensUntil = now.Add(time.Second)
+
+	// Act
+	transitionInfo, transitionErr := infrastructure.NewRealJwtManager(key, transitionPolicy, timeProvider).Parse(legacyToken)
+	afterTransitionInfo, afterTransitionErr := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, timeProvider).Parse(legacyToken)
+
+	// Assert
+	assert.NoError(t, transitionErr)
+	assert.Equal(t, info, transitionInfo)
+	assert.Nil(t, afterTransitionInfo)
+	assertTokenError(t, services.TokenClaimsInvalid, afterTransitionErr)
+}
+
+func TestParse_ForgedTokens(t *testing.T) {
+	// Arrange
+	key := []byte("123_secret_321")
+	info := &value_objects.AuthInfo{UserUuid: uuid.New(), ExpirationAt: time.Date(1986, time.April, 26, 1, 23, 47, 0, time.UTC)}
+	manager := infrastructure.NewRealJwtManager(key, jwtClaimsPolicy, newJwtTimeProvider())
+	forgedToken, _ := infrastructure.NewRealJwtManager([]byte("forged_secret"), jwtClaimsPolicy, newJwtTimeProvider()).Generate(info)
+	unsignedToken, _ := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": info.UserUuid.String()}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	// Act
+	_, forgedErr := manager.Parse(forgedToken)
+	_, unsignedErr := manager.Parse(unsignedToken)
+	_, malformedErr := manager.Parse("not a token")
+
+	// Assert
+	assertTokenError(t, services.TokenSignatureInvalid, forgedErr)
+	assertTokenError(t, services.TokenAlgorithmInvalid, unsignedErr)
+	assertTokenError(t, services.TokenMalformed, malformedErr)
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/entities"
+)
+
+type PosgresPasswordHistoryRepository struct {
+	transaction pgx.Tx
+}
+
+func newPosgresPasswordHistoryRepository(transaction pgx.Tx) *PosgresPasswordHistoryRepository {
+	return &PosgresPasswordHistoryRepository{transaction}
+}
+
+func (r *PosgresPasswordHistoryRepository) Create(ctx context.Context, entry *entities.PasswordHistoryEntry) error {
+	const query string = "INSERT INTO password_history VALUES ($1, $2, $3, $4, $5, $6)"
+
+	_, err := r.transaction.Exec(ctx, query, entry.Uuid, entry.UserUuid, entry.CreatedAt, entry.Password, entry.Salt, entry.PepperId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresPasswordHistoryRepository) GetLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, limit int) ([]*entities.PasswordHistoryEntry, error) {
+	const query string = "SELECT * FROM password_history WHERE user_uuid = $1 ORDER BY created_at DESC LIMIT $2"
+
+	rows, err := r.transaction.Query(ctx, query, userUuid, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*entities.PasswordHistoryEntry, 0)
+	for rows.Next() {
+		entry := &entities.PasswordHistoryEntry{}
+
+		err = rows.Scan(&entry.Uuid, &entry.UserUuid, &entry.CreatedAt, &entry.Password, &entry.Salt, &entry.PepperId)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *PosgresPasswordHistoryRepository) DeleteAllButLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, kept int) error {
+	const query string = `DELETE FROM password_history WHERE user_uuid = $1 AND uuid NOT IN (
+		SELECT uuid FROM password_history WHERE user_uuid = $1 ORDER BY created_at DESC LIMIT $2
+	)`
+
+	_, err := r.transaction.Exec(ctx, query, userUuid, kept)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+func NewMockPasswordHistoryRepository() *MockPasswordHistoryRepository {
+	return &MockPasswordHistoryRepository{}
+}
+
+func (r *MockPasswordHistoryRepository) Create(ctx context.Context, entry *entities.PasswordHistoryEntry) error {
+	args := r.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (r *MockPasswordHistoryRepository) GetLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, limit int) ([]*entities.PasswordHistoryEntry, error) {
+	args := r.Called(ctx, userUuid, limit)
+	return args.Get(0).([]*entities.PasswordHistoryEntry), args.Error(1)
+}
+
+func (r *MockPasswordHistoryRepository) DeleteAllButLatestByUserUuid(ctx context.Context, userUuid uuid.UUID, kept int) error {
+	args := r.Called(ctx, userUuid, kept)
+	return args.Error(0)
+}
@@ -0,0 +1,202 @@
+package infrastructure
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/value-objects"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const minSimilarNameLength = 3
+
+type RealPasswordPolicy struct {
+	minLength           int
+	maxLength           int
+	minCharacterClasses int
+	minEntropy          float64
+	bannedPasswords     map[string]struct{}
+}
+
+func NewRealPasswordPolicy(minLength, maxLength, minCharacterClasses int, minEntropy float64, bannedPasswords []string) *RealPasswordPolicy {
+	banned := make(map[string]struct{}, len(bannedPasswords))
+	for _, password := range bannedPasswords {
+		banned[strings.ToLower(password)] = struct{}{}
+	}
+
+	return &RealPasswordPolicy{minLength, maxLength, minCharacterClasses, minEntropy, banned}
+}
+
+// ReadBannedPasswords reads a list with one password per line. Empty lines and lines starting with # are skipped.
+func ReadBannedPasswords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var passwords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, line)
+	}
+
+	return passwords, scanner.Err()
+}
+
+func (p *RealPasswordPolicy) Check(name, password string) []value_objects.Violation {
+	var violations []value_objects.Violation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.minLength {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_TOO_SHORT",
+			Description: fmt.Sprintf("password must contain at least %d characters", p.minLength),
+		})
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_TOO_LONG",
+			Description: fmt.Sprintf("password must contain at most %d characters", p.maxLength),
+		})
+	}
+
+	if characterClasses(password) < p.minCharacterClasses {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_TOO_FEW_CHARACTER_CLASSES",
+			Description: fmt.Sprintf("password must contain characters of at least %d of the classes: lowercase letters, uppercase letters, digits, symbols", p.minCharacterClasses),
+		})
+	}
+
+	if isSimilarToName(name, password) {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_SIMILAR_TO_LOGIN",
+			Description: "password must not contain the login or be contained in it",
+		})
+	}
+
+	if estimateEntropy(password) < p.minEntropy {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_TOO_PREDICTABLE",
+			Description: "password is too predictable, use more distinct characters",
+		})
+	}
+
+	if _, banned := p.bannedPasswords[strings.ToLower(password)]; banned {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "PASSWORD_BANNED",
+			Description: "password is too common",
+		})
+	}
+
+	return violations
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+
+	return count
+}
+
+func isSimilarToName(name, password string) bool {
+	name = strings.ToLower(name)
+	password = strings.ToLower(password)
+	if utf8.RuneCountInString(name) < minSimilarNameLength || password == "" {
+		return false
+	}
+
+	return strings.Contains(password, name) || strings.Contains(name, password) || strings.Contains(password, reverse(name))
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// estimateEntropy multiplies the bits per character of the used alphabets by the number of distinct characters,
+// so repetitions like "aaaaaaaa" or "abababab" are not rewarded for their length.
+func estimateEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	distinct := make(map[rune]struct{})
+	for _, r := range password {
+		distinct[r] = struct{}{}
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < utf8.RuneSelf:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	alphabet := 0
+	if lower {
+		alphabet += 26
+	}
+	if upper {
+		alphabet += 26
+	}
+	if digit {
+		alphabet += 10
+	}
+	if symbol {
+		alphabet += 33
+	}
+	if other {
+		alphabet += 100
+	}
+	if alphabet == 0 {
+		return 0
+	}
+
+	return float64(len(distinct)) * math.Log2(float64(alphabet))
+}
+
+type MockPasswordPolicy struct {
+	mock.Mock
+}
+
+func NewMockPasswordPolicy() *MockPasswordPolicy {
+	return &MockPasswordPolicy{}
+}
+
+func (p *MockPasswordPolicy) Check(name, password string) []value_objects.Violation {
+	args := p.Called(name, password)
+	return args.Get(0).([]value_objects.Violation)
+}
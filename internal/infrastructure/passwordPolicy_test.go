@@ -0,0 +1,72 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/core/value-objects"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+)
+
+func reasons(violations []value_objects.Violation) []string {
+	var result []string
+	for _, violation := range violations {
+		result = append(result, violation.Reason)
+	}
+
+	return result
+}
+
+func TestPasswordPolicyCheck_StrongPassword(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealPasswordPolicy(8, 128, 3, 40, []string{"Password1!"})
+
+	// Act
+	violations := policy.Check("alice", "Tr0ub4dor&3-horse")
+
+	// Assert
+	assert.Empty(t, violations)
+}
+
+func TestPasswordPolicyCheck_EmptyPassword(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealPasswordPolicy(8, 128, 3, 40, nil)
+
+	// Act
+	violations := policy.Check("alice", "")
+
+	// Assert
+	assert.Equal(t, []string{"PASSWORD_TOO_SHORT", "PASSWORD_TOO_FEW_CHARACTER_CLASSES", "PASSWORD_TOO_PREDICTABLE"}, reasons(violations))
+}
+
+func TestPasswordPolicyCheck_SimilarToLogin(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealPasswordPolicy(8, 128, 3, 40, nil)
+
+	// Act
+	violations := policy.Check("Alexander", "#9ALEXANDER")
+
+	// Assert
+	assert.Contains(t, reasons(violations), "PASSWORD_SIMILAR_TO_LOGIN")
+}
+
+func TestPasswordPolicyCheck_BannedPassword(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealPasswordPolicy(8, 128, 3, 40, []string{"Password1!"})
+
+	// Act
+	violations := policy.Check("alice", "password1!")
+
+	// Assert
+	assert.Contains(t, reasons(violations), "PASSWORD_BANNED")
+}
+
+func TestPasswordPolicyCheck_RepeatedCharacters(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealPasswordPolicy(8, 128, 3, 40, nil)
+
+	// Act
+	violations := policy.Check("alice", "Aa1!Aa1!Aa1!Aa1!")
+
+	// Assert
+	assert.Equal(t, []string{"PASSWORD_TOO_PREDICTABLE"}, reasons(violations))
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/stretchr/testify/mock"
+)
+
+// HmacPepperer mixes a server-side secret into salted passwords with HMAC-SHA256. Every pepper has an id, which is
+// stored next to the hash, so retired peppers can still verify old hashes while new ones use the active pepper.
+// The empty id stands for hashes produced before peppering was introduced.
+type HmacPepperer struct {
+	activeId string
+	keys     map[string][]byte
+}
+
+// NewHmacPepperer expects base64-encoded keys. An empty activeId disables peppering of new hashes.
+func NewHmacPepperer(activeId string, encodedKeys map[string]string) (*HmacPepperer, error) {
+	keys := make(map[string][]byte, len(encodedKeys))
+	for id, encodedKey := range encodedKeys {
+		if id == "" {
+			return nil, fmt.Errorf("pepper id must not be empty")
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("pepper %q is not valid base64: %w", id, err)
+		}
+
+		keys[id] = key
+	}
+
+	if _, ok := keys[activeId]; activeId != "" && !ok {
+		return nil, fmt.Errorf("active pepper %q is not configured", activeId)
+	}
+
+	return &HmacPepperer{activeId, keys}, nil
+}
+
+func (p *HmacPepperer) ActiveId() string {
+	return p.activeId
+}
+
+func (p *HmacPepperer) Pepper(pepperId, saltedPassword string) (string, bool) {
+	if pepperId == "" {
+		return saltedPassword, true
+	}
+
+	key, ok := p.keys[pepperId]
+	if !ok {
+		return "", false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(saltedPassword))
+
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil)), true
+}
+
+type MockPepperer struct {
+	mock.Mock
+}
+
+func NewMockPepperer() *MockPepperer {
+	return &MockPepperer{}
+}
+
+func (p *MockPepperer) ActiveId() string {
+	args := p.Called()
+	return args.String(0)
+}
+
+func (p *MockPepperer) Pepper(pepperId, saltedPassword string) (string, bool) {
+	args := p.Called(pepperId, saltedPassword)
+	return args.String(0), args.Bool(1)
+}
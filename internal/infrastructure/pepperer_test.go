@@ -0,0 +1,40 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+)
+
+func TestPepper(t *testing.T) {
+	// Arrange
+	keys := map[string]string{"old": "b2xkX3NlY3JldA==", "new": "bmV3X3NlY3JldA=="}
+	pepperer, err := infrastructure.NewHmacPepperer("new", keys)
+	assert.NoError(t, err)
+
+	// Act
+	oldPepperedPassword, oldOk := pepperer.Pepper("old", "password + salt")
+	newPepperedPassword, newOk := pepperer.Pepper("new", "password + salt")
+	unpepperedPassword, unpepperedOk := pepperer.Pepper("", "password + salt")
+	_, unknownOk := pepperer.Pepper("unknown", "password + salt")
+
+	// Assert
+	assert.True(t, oldOk)
+	assert.True(t, newOk)
+	assert.NotEqual(t, oldPepperedPassword, newPepperedPassword)
+	assert.True(t, unpepperedOk)
+	assert.Equal(t, "password + salt", unpepperedPassword)
+	assert.False(t, unknownOk)
+	assert.Equal(t, "new", pepperer.ActiveId())
+}
+
+func TestNewHmacPepperer_ActivePepperIsMissing(t *testing.T) {
+	// Arrange
+	keys := map[string]string{"old": "b2xkX3NlY3JldA=="}
+
+	// Act
+	_, err := infrastructure.NewHmacPepperer("new", keys)
+
+	// Assert
+	assert.Error(t, err)
+}
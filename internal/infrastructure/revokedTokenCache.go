@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"grpc-auth/internal/core/services"
+	"sync"
+	"time"
+)
+
+// RealRevokedTokenCache holds all revoked tokens that have not expired yet. Tokens revoked by this instance are known
+// at once, tokens revoked by other instances once the cache is refreshed from the revoked_tokens table.
+type RealRevokedTokenCache struct {
+	mutex             sync.RWMutex
+	tokens            map[uuid.UUID]time.Time
+	leeway            time.Duration
+	unitOfWorkStarter services.UnitOfWorkStarter
+	timeProvider      services.TimeProvider
+}
+
+// NewRealRevokedTokenCache keeps tokens for the leeway after their expiration, as long as the JWT manager accepts them.
+func NewRealRevokedTokenCache(leeway time.Duration, unitOfWorkStarter services.UnitOfWorkStarter, timeProvider services.TimeProvider) *RealRevokedTokenCache {
+	return &RealRevokedTokenCache{tokens: make(map[uuid.UUID]time.Time), leeway: leeway, unitOfWorkStarter: unitOfWorkStarter, timeProvider: timeProvider}
+}
+
+func (c *RealRevokedTokenCache) Add(tokenUuid uuid.UUID, expirationAt time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.tokens[tokenUuid] = expirationAt
+}
+
+func (c *RealRevokedTokenCache) Contains(tokenUuid uuid.UUID) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	_, ok := c.tokens[tokenUuid]
+
+	return ok
+}
+
+// Refresh deletes the tokens that expired from the table and the cache and loads the tokens revoked by other instances.
+func (c *RealRevokedTokenCache) Refresh(ctx context.Context) error {
+	unitOfWork, err := c.unitOfWorkStarter.Start(ctx)
+	if err != nil {
+		return err
+	}
+	revokedTokenRepository := unitOfWork.RevokedTokenRepository()
+
+	expiredBefore := c.timeProvider.Now().Add(-c.leeway)
+
+	err = revokedTokenRepository.DeleteExpired(ctx, expiredBefore)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return err
+	}
+
+	revokedTokens, err := revokedTokenRepository.GetAll(ctx)
+	if err != nil {
+		_ = unitOfWork.Rollback(ctx)
+
+		return err
+	}
+
+	err = unitOfWork.Save(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Tokens added while the table was read may be missing from it, so the cache is merged instead of replaced
+	for tokenUuid, expirationAt := range c.tokens {
+		if expirationAt.Before(expiredBefore) {
+			delete(c.tokens, tokenUuid)
+		}
+	}
+	for _, revokedToken := range revokedTokens {
+		c.tokens[revokedToken.TokenUuid] = revokedToken.ExpirationAt
+	}
+
+	return nil
+}
+
+// Run refreshes the cache every interval until the context is done.
+func (c *RealRevokedTokenCache) Run(ctx context.Context, interval time.Duration, logger *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.Refresh(ctx)
+			if err != nil {
+				logger.Errorw("revoked token cache refresh failed", "error", err)
+			}
+		}
+	}
+}
+
+type MockRevokedTokenCache struct {
+	mock.Mock
+}
+
+func NewMockRevokedTokenCache() *MockRevokedTokenCache {
+	return &MockRevokedTokenCache{}
+}
+
+func (c *MockRevokedTokenCache) Add(tokenUuid uuid.UUID, expirationAt time.Time) {
+	c.Called(tokenUuid, expirationAt)
+}
+
+func (c *MockRevokedTokenCache) Contains(tokenUuid uuid.UUID) bool {
+	args := c.Called(tokenUuid)
+	return args.Bool(0)
+}
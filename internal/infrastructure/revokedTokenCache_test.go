@@ -0,0 +1,51 @@
+package infrastructure_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/core/entities"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+	"time"
+)
+
+func TestRefresh_MergesRevokedTokensAndEvictsExpired(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	const leeway = 30 * time.Second
+	now := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	userUuid := uuid.MustParse("0c5e3b9a-2f1d-4e8b-a7c6-1d2e3f4a5b6c")
+	expiredUuid := uuid.MustParse("1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d")
+	localUuid := uuid.MustParse("2b3c4d5e-6f7a-4b2c-9d3e-4f5a6b7c8d9e")
+	remoteUuid := uuid.MustParse("3c4d5e6f-7a8b-4c3d-8e4f-5a6b7c8d9e0f")
+
+	revokedTokenRepository := infrastructure.NewMockRevokedTokenRepository()
+	unitOfWork := infrastructure.NewMockUnitOfWork()
+	unitOfWorkStarter := infrastructure.NewMockUnitOfWorkStarter()
+	timeProvider := infrastructure.NewMockTimeProvider()
+
+	remoteToken := entities.NewRevokedToken(remoteUuid, userUuid, now.Add(-time.Minute), now.Add(time.Minute))
+
+	unitOfWorkStarter.On("Start", ctx).Return(unitOfWork, nil)
+	unitOfWork.On("RevokedTokenRepository").Return(revokedTokenRepository)
+	unitOfWork.On("Save", ctx).Return(nil)
+	revokedTokenRepository.On("DeleteExpired", ctx, now.Add(-leeway)).Return(nil)
+	revokedTokenRepository.On("GetAll", ctx).Return([]*entities.RevokedToken{remoteToken}, nil)
+	timeProvider.On("Now").Return(now)
+
+	cache := infrastructure.NewRealRevokedTokenCache(leeway, unitOfWorkStarter, timeProvider)
+	cache.Add(expiredUuid, now.Add(-leeway-time.Second))
+	cache.Add(localUuid, now.Add(time.Minute))
+
+	// Act
+	err := cache.Refresh(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, cache.Contains(expiredUuid))
+	assert.True(t, cache.Contains(localUuid))
+	assert.True(t, cache.Contains(remoteUuid))
+	revokedTokenRepository.AssertCalled(t, "DeleteExpired", ctx, now.Add(-leeway))
+	unitOfWork.AssertCalled(t, "Save", ctx)
+}
@@ -0,0 +1,87 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/entities"
+	"time"
+)
+
+type PosgresRevokedTokenRepository struct {
+	transaction pgx.Tx
+}
+
+func newPosgresRevokedTokenRepository(transaction pgx.Tx) *PosgresRevokedTokenRepository {
+	return &PosgresRevokedTokenRepository{transaction}
+}
+
+// Create ignores tokens that are already revoked.
+func (r *PosgresRevokedTokenRepository) Create(ctx context.Context, token *entities.RevokedToken) error {
+	const query string = "INSERT INTO revoked_tokens VALUES ($1, $2, $3, $4) ON CONFLICT (token_uuid) DO NOTHING"
+
+	_, err := r.transaction.Exec(ctx, query, token.TokenUuid, token.UserUuid, token.RevokedAt, token.ExpirationAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresRevokedTokenRepository) GetAll(ctx context.Context) ([]*entities.RevokedToken, error) {
+	const query string = "SELECT * FROM revoked_tokens"
+
+	rows, err := r.transaction.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]*entities.RevokedToken, 0)
+	for rows.Next() {
+		token := &entities.RevokedToken{}
+
+		err = rows.Scan(&token.TokenUuid, &token.UserUuid, &token.RevokedAt, &token.ExpirationAt)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *PosgresRevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	const query string = "DELETE FROM revoked_tokens WHERE expiration_at < $1"
+
+	_, err := r.transaction.Exec(ctx, query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type MockRevokedTokenRepository struct {
+	mock.Mock
+}
+
+func NewMockRevokedTokenRepository() *MockRevokedTokenRepository {
+	return &MockRevokedTokenRepository{}
+}
+
+func (r *MockRevokedTokenRepository) Create(ctx context.Context, token *entities.RevokedToken) error {
+	args := r.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (r *MockRevokedTokenRepository) GetAll(ctx context.Context) ([]*entities.RevokedToken, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]*entities.RevokedToken), args.Error(1)
+}
+
+func (r *MockRevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	args := r.Called(ctx, now)
+	return args.Error(0)
+}
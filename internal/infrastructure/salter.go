@@ -1,11 +1,15 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"time"
 )
 
+const saltLength = 32
+
 const staticSalt string = "-\\S?-bPGZO{n!]o6&8VvL2;oR*E7f~~pQe-;b*Z9qKkZ]HB<zLYC*PP1q>=Y^{gT"
 
 type RealSalter struct{}
@@ -14,7 +18,19 @@ func NewRealSalter() *RealSalter {
 	return &RealSalter{}
 }
 
-func (s *RealSalter) Salt(uuid uuid.UUID, createdAt time.Time, name, password string) string {
+func (s *RealSalter) Generate() string {
+	salt := make([]byte, saltLength)
+	_, _ = rand.Read(salt)
+
+	return base64.RawStdEncoding.EncodeToString(salt)
+}
+
+func (s *RealSalter) Salt(salt, password string) string {
+	return salt + password
+}
+
+// LegacySalt reproduces the salting of users created before per-user salts were introduced.
+func (s *RealSalter) LegacySalt(uuid uuid.UUID, createdAt time.Time, name, password string) string {
 	uuidString := uuid.String()
 	createdAtString := createdAt.String()
 	return createdAtString + staticSalt + name + staticSalt + password + createdAtString + uuidString + uuidString
@@ -28,7 +44,17 @@ func NewMockSalter() *MockSalter {
 	return &MockSalter{}
 }
 
-func (s *MockSalter) Salt(uuid uuid.UUID, createdAt time.Time, name, password string) string {
+func (s *MockSalter) Generate() string {
+	args := s.Called()
+	return args.String(0)
+}
+
+func (s *MockSalter) Salt(salt, password string) string {
+	args := s.Called(salt, password)
+	return args.String(0)
+}
+
+func (s *MockSalter) LegacySalt(uuid uuid.UUID, createdAt time.Time, name, password string) string {
 	args := s.Called(uuid, createdAt, name, password)
 	return args.String(0)
 }
@@ -9,6 +9,9 @@ import (
 	"grpc-auth/internal/core/entities"
 )
 
+// sessionColumns are listed explicitly, so the queries do not depend on the column order of upgraded databases
+const sessionColumns string = "refresh_token, user_uuid, expiration_at, uuid, created_at, refreshed_at, client_ip, user_agent"
+
 type PosgresSessionRepository struct {
 	transaction pgx.Tx
 }
@@ -18,9 +21,9 @@ func newPosgresSessionRepository(transaction pgx.Tx) *PosgresSessionRepository {
 }
 
 func (r *PosgresSessionRepository) Create(ctx context.Context, session *entities.Session) error {
-	const query string = "INSERT INTO sessions VALUES ($1, $2, $3)"
+	const query string = "INSERT INTO sessions (" + sessionColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
 
-	_, err := r.transaction.Exec(ctx, query, session.RefreshToken, session.UserUuid, session.ExpirationAt)
+	_, err := r.transaction.Exec(ctx, query, session.RefreshToken, session.UserUuid, session.ExpirationAt, session.Uuid, session.CreatedAt, session.RefreshedAt, session.ClientIp, session.UserAgent)
 	if err != nil {
 		return err
 	}
@@ -29,11 +32,11 @@ func (r *PosgresSessionRepository) Create(ctx context.Context, session *entities
 }
 
 func (r *PosgresSessionRepository) TryGetByRefreshToken(ctx context.Context, refreshToken uuid.UUID) (*entities.Session, error) {
-	const query string = "SELECT * FROM sessions WHERE refresh_token = $1 FOR UPDATE"
+	const query string = "SELECT " + sessionColumns + " FROM sessions WHERE refresh_token = $1 FOR UPDATE"
 
 	session := &entities.Session{}
 
-	err := r.transaction.QueryRow(ctx, query, refreshToken).Scan(&session.RefreshToken, &session.UserUuid, &session.ExpirationAt)
+	err := scanSession(r.transaction.QueryRow(ctx, query, refreshToken), session)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -45,6 +48,30 @@ func (r *PosgresSessionRepository) TryGetByRefreshToken(ctx context.Context, ref
 	return session, nil
 }
 
+func (r *PosgresSessionRepository) GetAllByUserUuid(ctx context.Context, userUuid uuid.UUID) ([]*entities.Session, error) {
+	const query string = "SELECT " + sessionColumns + " FROM sessions WHERE user_uuid = $1 ORDER BY refreshed_at DESC"
+
+	rows, err := r.transaction.Query(ctx, query, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*entities.Session, 0)
+	for rows.Next() {
+		session := &entities.Session{}
+
+		err = scanSession(rows, session)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
 func (r *PosgresSessionRepository) DeleteByRefreshToken(ctx context.Context, refreshToken uuid.UUID) error {
 	const query string = "DELETE FROM sessions WHERE refresh_token = $1"
 
@@ -56,6 +83,62 @@ func (r *PosgresSessionRepository) DeleteByRefreshToken(ctx context.Context, ref
 	return nil
 }
 
+func (r *PosgresSessionRepository) DeleteByUserUuid(ctx context.Context, userUuid, keptRefreshToken uuid.UUID) error {
+	const query string = "DELETE FROM sessions WHERE user_uuid = $1 AND refresh_token <> $2"
+
+	_, err := r.transaction.Exec(ctx, query, userUuid, keptRefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresSessionRepository) TryDeleteByUuid(ctx context.Context, sessionUuid, userUuid uuid.UUID) (bool, error) {
+	const query string = "DELETE FROM sessions WHERE uuid = $1 AND user_uuid = $2"
+
+	commandTag, err := r.transaction.Exec(ctx, query, sessionUuid, userUuid)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() > 0, nil
+}
+
+func (r *PosgresSessionRepository) ExistsByUuid(ctx context.Context, sessionUuid uuid.UUID) (bool, error) {
+	const query string = "SELECT EXISTS(SELECT 1 FROM sessions WHERE uuid = $1)"
+
+	var exists bool
+	err := r.transaction.QueryRow(ctx, query, sessionUuid).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// TryGetByUuid does not lock the session, it is meant for reading only.
+func (r *PosgresSessionRepository) TryGetByUuid(ctx context.Context, sessionUuid uuid.UUID) (*entities.Session, error) {
+	const query string = "SELECT " + sessionColumns + " FROM sessions WHERE uuid = $1"
+
+	session := &entities.Session{}
+
+	err := scanSession(r.transaction.QueryRow(ctx, query, sessionUuid), session)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func scanSession(row pgx.Row, session *entities.Session) error {
+	return row.Scan(&session.RefreshToken, &session.UserUuid, &session.ExpirationAt, &session.Uuid, &session.CreatedAt, &session.RefreshedAt, &session.ClientIp, &session.UserAgent)
+}
+
 type MockSessionRepository struct {
 	mock.Mock
 }
@@ -72,7 +155,32 @@ func (r *MockSessionRepository) TryGetByRefreshToken(ctx context.Context, refres
 	return args.Get(0).(*entities.Session), args.Error(1)
 }
 
+func (r *MockSessionRepository) GetAllByUserUuid(ctx context.Context, userUuid uuid.UUID) ([]*entities.Session, error) {
+	args := r.Called(ctx, userUuid)
+	return args.Get(0).([]*entities.Session), args.Error(1)
+}
+
 func (r *MockSessionRepository) DeleteByRefreshToken(ctx context.Context, refreshToken uuid.UUID) error {
 	args := r.Called(ctx, refreshToken)
 	return args.Error(0)
 }
+
+func (r *MockSessionRepository) DeleteByUserUuid(ctx context.Context, userUuid, keptRefreshToken uuid.UUID) error {
+	args := r.Called(ctx, userUuid, keptRefreshToken)
+	return args.Error(0)
+}
+
+func (r *MockSessionRepository) TryDeleteByUuid(ctx context.Context, sessionUuid, userUuid uuid.UUID) (bool, error) {
+	args := r.Called(ctx, sessionUuid, userUuid)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *MockSessionRepository) ExistsByUuid(ctx context.Context, sessionUuid uuid.UUID) (bool, error) {
+	args := r.Called(ctx, sessionUuid)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *MockSessionRepository) TryGetByUuid(ctx context.Context, sessionUuid uuid.UUID) (*entities.Session, error) {
+	args := r.Called(ctx, sessionUuid)
+	return args.Get(0).(*entities.Session), args.Error(1)
+}
@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"grpc-auth/internal/core/entities"
+	"time"
+)
+
+type PosgresSigningKeyRepository struct {
+	transaction pgx.Tx
+}
+
+func newPosgresSigningKeyRepository(transaction pgx.Tx) *PosgresSigningKeyRepository {
+	return &PosgresSigningKeyRepository{transaction}
+}
+
+// Lock serializes rotations of all service instances until the end of the transaction.
+func (r *PosgresSigningKeyRepository) Lock(ctx context.Context) error {
+	const query string = "LOCK TABLE signing_keys IN EXCLUSIVE MODE"
+
+	_, err := r.transaction.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresSigningKeyRepository) Create(ctx context.Context, key *entities.SigningKey) error {
+	const query string = "INSERT INTO signing_keys VALUES ($1, $2, $3, $4, $5, $6)"
+
+	_, err := r.transaction.Exec(ctx, query, key.Id, key.Algorithm, key.EncryptedKey, key.CreatedAt, key.ActivatesAt, nullableTime(key.RetiresAt))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresSigningKeyRepository) GetAll(ctx context.Context) ([]*entities.SigningKey, error) {
+	const query string = "SELECT * FROM signing_keys ORDER BY created_at"
+
+	rows, err := r.transaction.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*entities.SigningKey, 0)
+	for rows.Next() {
+		key := &entities.SigningKey{}
+		var retiresAt *time.Time
+
+		err = rows.Scan(&key.Id, &key.Algorithm, &key.EncryptedKey, &key.CreatedAt, &key.ActivatesAt, &retiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if retiresAt != nil {
+			key.RetiresAt = *retiresAt
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *PosgresSigningKeyRepository) Update(ctx context.Context, key *entities.SigningKey) error {
+	const query string = "UPDATE signing_keys SET activates_at = $2, retires_at = $3 WHERE id = $1"
+
+	_, err := r.transaction.Exec(ctx, query, key.Id, key.ActivatesAt, nullableTime(key.RetiresAt))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *PosgresSigningKeyRepository) DeleteRetired(ctx context.Context, now time.Time) error {
+	const query string = "DELETE FROM signing_keys WHERE retires_at <= $1"
+
+	_, err := r.transaction.Exec(ctx, query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+type MockSigningKeyRepository struct {
+	mock.Mock
+}
+
+func NewMockSigningKeyRepository() *MockSigningKeyRepository {
+	return &MockSigningKeyRepository{}
+}
+
+func (r *MockSigningKeyRepository) Lock(ctx context.Context) error {
+	args := r.Called(ctx)
+	return args.Error(0)
+}
+
+func (r *MockSigningKeyRepository) Create(ctx context.Context, key *entities.SigningKey) error {
+	args := r.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (r *MockSigningKeyRepository) GetAll(ctx context.Context) ([]*entities.SigningKey, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]*entities.SigningKey), args.Error(1)
+}
+
+func (r *MockSigningKeyRepository) Update(ctx context.Context, key *entities.SigningKey) error {
+	args := r.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (r *MockSigningKeyRepository) DeleteRetired(ctx context.Context, now time.Time) error {
+	args := r.Called(ctx, now)
+	return args.Error(0)
+}
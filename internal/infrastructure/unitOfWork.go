@@ -8,13 +8,16 @@ import (
 )
 
 type postgresUnitOfWork struct {
-	transaction       pgx.Tx
-	userRepository    *PosgresUserRepository
-	sessionRepository *PosgresSessionRepository
+	transaction               pgx.Tx
+	userRepository            *PosgresUserRepository
+	sessionRepository         *PosgresSessionRepository
+	passwordHistoryRepository *PosgresPasswordHistoryRepository
+	signingKeyRepository      *PosgresSigningKeyRepository
+	revokedTokenRepository    *PosgresRevokedTokenRepository
 }
 
 func newPostgresUnitOfWork(transaction pgx.Tx) *postgresUnitOfWork {
-	return &postgresUnitOfWork{transaction, newPosgresUserRepository(transaction), newPosgresSessionRepository(transaction)}
+	return &postgresUnitOfWork{transaction, newPosgresUserRepository(transaction), newPosgresSessionRepository(transaction), newPosgresPasswordHistoryRepository(transaction), newPosgresSigningKeyRepository(transaction), newPosgresRevokedTokenRepository(transaction)}
 }
 
 func (uow *postgresUnitOfWork) UserRepository() services.UserRepository {
@@ -25,6 +28,18 @@ func (uow *postgresUnitOfWork) SessionRepository() services.SessionRepository {
 	return uow.sessionRepository
 }
 
+func (uow *postgresUnitOfWork) PasswordHistoryRepository() services.PasswordHistoryRepository {
+	return uow.passwordHistoryRepository
+}
+
+func (uow *postgresUnitOfWork) SigningKeyRepository() services.SigningKeyRepository {
+	return uow.signingKeyRepository
+}
+
+func (uow *postgresUnitOfWork) RevokedTokenRepository() services.RevokedTokenRepository {
+	return uow.revokedTokenRepository
+}
+
 func (uow *postgresUnitOfWork) Save(ctx context.Context) error {
 	return uow.transaction.Commit(ctx)
 }
@@ -51,6 +66,21 @@ func (uow *MockUnitOfWork) SessionRepository() services.SessionRepository {
 	return args.Get(0).(services.SessionRepository)
 }
 
+func (uow *MockUnitOfWork) PasswordHistoryRepository() services.PasswordHistoryRepository {
+	args := uow.Called()
+	return args.Get(0).(services.PasswordHistoryRepository)
+}
+
+func (uow *MockUnitOfWork) SigningKeyRepository() services.SigningKeyRepository {
+	args := uow.Called()
+	return args.Get(0).(services.SigningKeyRepository)
+}
+
+func (uow *MockUnitOfWork) RevokedTokenRepository() services.RevokedTokenRepository {
+	args := uow.Called()
+	return args.Get(0).(services.RevokedTokenRepository)
+}
+
 func (uow *MockUnitOfWork) Save(ctx context.Context) error {
 	args := uow.Called(ctx)
 	return args.Error(0)
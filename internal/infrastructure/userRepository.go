@@ -8,8 +8,12 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/mock"
 	"grpc-auth/internal/core/entities"
+	"time"
 )
 
+// userColumns are listed explicitly, upgraded databases have the columns in a different order than a fresh schema
+const userColumns string = "uuid, created_at, name, normalized_name, password, salt, pepper_id, password_changed_at, tokens_valid_after"
+
 type PosgresUserRepository struct {
 	transaction pgx.Tx
 }
@@ -19,9 +23,9 @@ func newPosgresUserRepository(transaction pgx.Tx) *PosgresUserRepository {
 }
 
 func (r *PosgresUserRepository) TryCreate(ctx context.Context, user *entities.User) (bool, error) {
-	const query string = "INSERT INTO users VALUES ($1, $2, $3, $4)"
+	const query string = "INSERT INTO users (" + userColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
 
-	_, err := r.transaction.Exec(ctx, query, user.Uuid, user.CreatedAt, user.Name, user.Password)
+	_, err := r.transaction.Exec(ctx, query, user.Uuid, user.CreatedAt, user.Name, user.NormalizedName, user.Password, user.Salt, user.PepperId, user.PasswordChangedAt, nullableTime(user.TokensValidAfter))
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Check unique_violation PostgreSQL error
@@ -34,12 +38,12 @@ func (r *PosgresUserRepository) TryCreate(ctx context.Context, user *entities.Us
 	return true, nil
 }
 
-func (r *PosgresUserRepository) TryGetByName(ctx context.Context, name string) (*entities.User, error) {
-	const query string = "SELECT * FROM users WHERE name = $1 FOR UPDATE"
+func (r *PosgresUserRepository) TryGetByName(ctx context.Context, normalizedName string) (*entities.User, error) {
+	const query string = "SELECT " + userColumns + " FROM users WHERE normalized_name = $1 FOR UPDATE"
 
 	user := &entities.User{}
 
-	err := r.transaction.QueryRow(ctx, query, name).Scan(&user.Uuid, &user.CreatedAt, &user.Name, &user.Password)
+	err := scanUser(r.transaction.QueryRow(ctx, query, normalizedName), user)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -51,6 +55,39 @@ func (r *PosgresUserRepository) TryGetByName(ctx context.Context, name string) (
 	return user, nil
 }
 
+func (r *PosgresUserRepository) TryGetByUuid(ctx context.Context, userUuid uuid.UUID) (*entities.User, error) {
+	const query string = "SELECT " + userColumns + " FROM users WHERE uuid = $1 FOR UPDATE"
+
+	user := &entities.User{}
+
+	err := scanUser(r.transaction.QueryRow(ctx, query, userUuid), user)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *PosgresUserRepository) TryUpdate(ctx context.Context, user *entities.User) (bool, error) {
+	const query string = "UPDATE users SET name = $2, normalized_name = $3, password = $4, salt = $5, pepper_id = $6, password_changed_at = $7, tokens_valid_after = $8 WHERE uuid = $1"
+
+	_, err := r.transaction.Exec(ctx, query, user.Uuid, user.Name, user.NormalizedName, user.Password, user.Salt, user.PepperId, user.PasswordChangedAt, nullableTime(user.TokensValidAfter))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Check unique_violation PostgreSQL error
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *PosgresUserRepository) TryDelete(ctx context.Context, userUuid uuid.UUID) (bool, error) {
 	const query string = "SELECT EXISTS(DELETE FROM users WHERE uuid = $1 RETURNING TRUE)"
 
@@ -63,16 +100,39 @@ func (r *PosgresUserRepository) TryDelete(ctx context.Context, userUuid uuid.UUI
 	return deleted, nil
 }
 
-func (r *PosgresUserRepository) Exists(ctx context.Context, userUuid uuid.UUID) (bool, error) {
-	const query string = "SELECT EXISTS(SELECT 1 FROM users WHERE uuid = $1)"
+// TryGetTokensValidAfter returns false when the user does not exist. The user is locked for share, unlike TryGetByUuid
+// it does not block other readers, but the cut-off cannot change until the transaction ends.
+func (r *PosgresUserRepository) TryGetTokensValidAfter(ctx context.Context, userUuid uuid.UUID) (time.Time, bool, error) {
+	const query string = "SELECT tokens_valid_after FROM users WHERE uuid = $1 FOR SHARE"
 
-	var exists bool
-	err := r.transaction.QueryRow(ctx, query, userUuid).Scan(&exists)
+	var tokensValidAfter *time.Time
+	err := r.transaction.QueryRow(ctx, query, userUuid).Scan(&tokensValidAfter)
 	if err != nil {
-		return false, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+
+		return time.Time{}, false, err
+	}
+	if tokensValidAfter == nil {
+		return time.Time{}, true, nil
+	}
+
+	return *tokensValidAfter, true, nil
+}
+
+func scanUser(row pgx.Row, user *entities.User) error {
+	var tokensValidAfter *time.Time
+
+	err := row.Scan(&user.Uuid, &user.CreatedAt, &user.Name, &user.NormalizedName, &user.Password, &user.Salt, &user.PepperId, &user.PasswordChangedAt, &tokensValidAfter)
+	if err != nil {
+		return err
+	}
+	if tokensValidAfter != nil {
+		user.TokensValidAfter = *tokensValidAfter
 	}
 
-	return exists, nil
+	return nil
 }
 
 type MockUserRepository struct {
@@ -86,17 +146,27 @@ func (r *MockUserRepository) TryCreate(ctx context.Context, user *entities.User)
 	return args.Bool(0), args.Error(1)
 }
 
-func (r *MockUserRepository) TryGetByName(ctx context.Context, name string) (*entities.User, error) {
-	args := r.Called(ctx, name)
+func (r *MockUserRepository) TryGetByName(ctx context.Context, normalizedName string) (*entities.User, error) {
+	args := r.Called(ctx, normalizedName)
 	return args.Get(0).(*entities.User), args.Error(1)
 }
 
-func (r *MockUserRepository) TryDelete(ctx context.Context, userUuid uuid.UUID) (bool, error) {
+func (r *MockUserRepository) TryGetByUuid(ctx context.Context, userUuid uuid.UUID) (*entities.User, error) {
 	args := r.Called(ctx, userUuid)
+	return args.Get(0).(*entities.User), args.Error(1)
+}
+
+func (r *MockUserRepository) TryUpdate(ctx context.Context, user *entities.User) (bool, error) {
+	args := r.Called(ctx, user)
 	return args.Bool(0), args.Error(1)
 }
 
-func (r *MockUserRepository) Exists(ctx context.Context, userUuid uuid.UUID) (bool, error) {
+func (r *MockUserRepository) TryDelete(ctx context.Context, userUuid uuid.UUID) (bool, error) {
 	args := r.Called(ctx, userUuid)
 	return args.Bool(0), args.Error(1)
 }
+
+func (r *MockUserRepository) TryGetTokensValidAfter(ctx context.Context, userUuid uuid.UUID) (time.Time, bool, error) {
+	args := r.Called(ctx, userUuid)
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"grpc-auth/internal/core/value-objects"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scripts a username may be written in. Letters of different scripts must not be mixed, so names like "аdmin"
+// with a Cyrillic "а" cannot imitate other names. Japanese mixes Han, Hiragana and Katakana, so they count as one script.
+var usernameScripts = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{"Latin", []*unicode.RangeTable{unicode.Latin}},
+	{"Cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"Greek", []*unicode.RangeTable{unicode.Greek}},
+	{"Armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"Georgian", []*unicode.RangeTable{unicode.Georgian}},
+	{"Hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"Arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"Devanagari", []*unicode.RangeTable{unicode.Devanagari}},
+	{"Thai", []*unicode.RangeTable{unicode.Thai}},
+	{"Hangul", []*unicode.RangeTable{unicode.Hangul}},
+	{"Japanese", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana}},
+}
+
+type RealUsernamePolicy struct {
+	minLength     int
+	maxLength     int
+	reservedNames map[string]struct{}
+}
+
+func NewRealUsernamePolicy(minLength, maxLength int, reservedNames []string) *RealUsernamePolicy {
+	policy := &RealUsernamePolicy{minLength, maxLength, make(map[string]struct{}, len(reservedNames))}
+	for _, name := range reservedNames {
+		policy.reservedNames[policy.Normalize(name)] = struct{}{}
+	}
+
+	return policy
+}
+
+// Normalize applies NFKC and case folding, so compatibility forms like "ｆｕｌｌｗｉｄｔｈ" and different cases share one form.
+// NFKC is applied again after folding because folding can produce unnormalized sequences.
+func (p *RealUsernamePolicy) Normalize(name string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(name)))
+}
+
+func (p *RealUsernamePolicy) Check(name string) []value_objects.Violation {
+	var violations []value_objects.Violation
+
+	normalizedName := p.Normalize(name)
+
+	length := utf8.RuneCountInString(normalizedName)
+	if length < p.minLength {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "USERNAME_TOO_SHORT",
+			Description: fmt.Sprintf("username must contain at least %d characters", p.minLength),
+		})
+	}
+	if p.maxLength > 0 && length > p.maxLength {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "USERNAME_TOO_LONG",
+			Description: fmt.Sprintf("username must contain at most %d characters", p.maxLength),
+		})
+	}
+
+	if !hasAllowedCharacters(normalizedName) {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "USERNAME_INVALID_CHARACTERS",
+			Description: "username may only contain letters, digits, underscores, hyphens and dots",
+		})
+	}
+
+	if hasMixedScripts(normalizedName) {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "USERNAME_MIXED_SCRIPTS",
+			Description: "username must not mix letters of different scripts",
+		})
+	}
+
+	if _, reserved := p.reservedNames[normalizedName]; reserved {
+		violations = append(violations, value_objects.Violation{
+			Reason:      "USERNAME_RESERVED",
+			Description: "username is reserved",
+		})
+	}
+
+	return violations
+}
+
+func hasAllowedCharacters(name string) bool {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasMixedScripts(name string) bool {
+	script := ""
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letterScript := "Other"
+		for _, candidate := range usernameScripts {
+			if unicode.IsOneOf(candidate.tables, r) {
+				letterScript = candidate.name
+				break
+			}
+		}
+
+		if script == "" {
+			script = letterScript
+		} else if script != letterScript {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MockUsernamePolicy struct {
+	mock.Mock
+}
+
+func NewMockUsernamePolicy() *MockUsernamePolicy {
+	return &MockUsernamePolicy{}
+}
+
+func (p *MockUsernamePolicy) Normalize(name string) string {
+	args := p.Called(name)
+	return args.String(0)
+}
+
+func (p *MockUsernamePolicy) Check(name string) []value_objects.Violation {
+	args := p.Called(name)
+	return args.Get(0).([]value_objects.Violation)
+}
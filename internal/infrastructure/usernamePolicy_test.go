@@ -0,0 +1,52 @@
+package infrastructure_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"grpc-auth/internal/infrastructure"
+	"testing"
+)
+
+func TestUsernamePolicyNormalize(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealUsernamePolicy(3, 32, nil)
+
+	// Act
+	upper := policy.Normalize("ALICE")
+	fullwidth := policy.Normalize("ａｌｉｃｅ")
+	sharpS := policy.Normalize("Straße")
+
+	// Assert
+	assert.Equal(t, "alice", upper)
+	assert.Equal(t, "alice", fullwidth)
+	assert.Equal(t, "strasse", sharpS)
+}
+
+func TestUsernamePolicyCheck_ValidName(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealUsernamePolicy(3, 32, []string{"admin"})
+
+	// Act
+	latin := policy.Check("Alice_Smith-1.0")
+	cyrillic := policy.Check("Алиса")
+
+	// Assert
+	assert.Empty(t, latin)
+	assert.Empty(t, cyrillic)
+}
+
+func TestUsernamePolicyCheck_InvalidName(t *testing.T) {
+	// Arrange
+	policy := infrastructure.NewRealUsernamePolicy(3, 32, []string{"admin"})
+
+	// Act
+	empty := policy.Check("")
+	whitespace := policy.Check("   ")
+	mixedScripts := policy.Check("pаypal") // The second letter is Cyrillic
+	reserved := policy.Check("ＡＤＭＩＮ")
+
+	// Assert
+	assert.Equal(t, []string{"USERNAME_TOO_SHORT"}, reasons(empty))
+	assert.Equal(t, []string{"USERNAME_INVALID_CHARACTERS"}, reasons(whitespace))
+	assert.Equal(t, []string{"USERNAME_MIXED_SCRIPTS"}, reasons(mixedScripts))
+	assert.Equal(t, []string{"USERNAME_RESERVED"}, reasons(reserved))
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+)
+
+// clientInfo extracts the address and the user agent of the caller. Both are informational only and are empty when
+// the transport does not provide them.
+func clientInfo(ctx context.Context) (clientIp, userAgent string) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		clientIp = p.Addr.String()
+		if host, _, err := net.SplitHostPort(clientIp); err == nil {
+			clientIp = host
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			userAgent = values[0]
+		}
+	}
+
+	return clientIp, userAgent
+}
@@ -3,8 +3,10 @@ package auth
 import (
 	"context"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"grpc-auth/grpc/gen"
 	service "grpc-auth/internal/core/services/auth"
+	"time"
 )
 
 type Controller struct {
@@ -43,17 +45,19 @@ func mapRegisterResponse(source *service.RegisterResponse) *auth.RegisterRespons
 }
 
 func (s *Controller) Login(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
-	ret, err := s.service.Login(ctx, mapLoginRequest(req))
+	ret, err := s.service.Login(ctx, mapLoginRequest(ctx, req))
 
 	return mapLoginResponse(ret), err
 }
 
-func mapLoginRequest(source *auth.LoginRequest) *service.LoginRequest {
+func mapLoginRequest(ctx context.Context, source *auth.LoginRequest) *service.LoginRequest {
 	if source == nil {
 		return nil
 	}
 
-	return &service.LoginRequest{Name: source.Username, Password: source.Password}
+	clientIp, userAgent := clientInfo(ctx)
+
+	return &service.LoginRequest{Name: source.Username, Password: source.Password, ClientIp: clientIp, UserAgent: userAgent}
 }
 
 func mapLoginResponse(source *service.LoginResponse) *auth.LoginResponse {
@@ -61,7 +65,7 @@ func mapLoginResponse(source *service.LoginResponse) *auth.LoginResponse {
 		return nil
 	}
 
-	return &auth.LoginResponse{RefreshToken: source.RefreshToken, AccessToken: source.AccessToken}
+	return &auth.LoginResponse{RefreshToken: source.RefreshToken, AccessToken: source.AccessToken, PasswordExpired: source.PasswordExpired}
 }
 
 func (s *Controller) DeleteUser(ctx context.Context, req *auth.DeleteUserRequest) (*auth.DeleteUserResponse, error) {
@@ -86,18 +90,178 @@ func mapDeleteUserResponse(source *service.DeleteUserResponse) *auth.DeleteUserR
 	return &auth.DeleteUserResponse{Message: source.Message}
 }
 
+func (s *Controller) DeleteSession(ctx context.Context, req *auth.DeleteSessionRequest) (*auth.DeleteSessionResponse, error) {
+	ret, err := s.service.DeleteSession(ctx, mapDeleteSessionRequest(req))
+
+	return mapDeleteSessionResponse(ret), err
+}
+
+func mapDeleteSessionRequest(source *auth.DeleteSessionRequest) *service.DeleteSessionRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.DeleteSessionRequest{RefreshToken: source.RefreshToken}
+}
+
+func mapDeleteSessionResponse(source *service.DeleteSessionResponse) *auth.DeleteSessionResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.DeleteSessionResponse{Message: source.Message}
+}
+
+func (s *Controller) DeleteAllSessions(ctx context.Context, req *auth.DeleteAllSessionsRequest) (*auth.DeleteAllSessionsResponse, error) {
+	ret, err := s.service.DeleteAllSessions(ctx, mapDeleteAllSessionsRequest(req))
+
+	return mapDeleteAllSessionsResponse(ret), err
+}
+
+func mapDeleteAllSessionsRequest(source *auth.DeleteAllSessionsRequest) *service.DeleteAllSessionsRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.DeleteAllSessionsRequest{AccessToken: source.AccessToken, RefreshToken: source.RefreshToken}
+}
+
+func mapDeleteAllSessionsResponse(source *service.DeleteAllSessionsResponse) *auth.DeleteAllSessionsResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.DeleteAllSessionsResponse{Message: source.Message}
+}
+
+func (s *Controller) ListSessions(ctx context.Context, req *auth.ListSessionsRequest) (*auth.ListSessionsResponse, error) {
+	ret, err := s.service.ListSessions(ctx, mapListSessionsRequest(req))
+
+	return mapListSessionsResponse(ret), err
+}
+
+func mapListSessionsRequest(source *auth.ListSessionsRequest) *service.ListSessionsRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.ListSessionsRequest{AccessToken: source.AccessToken}
+}
+
+func mapListSessionsResponse(source *service.ListSessionsResponse) *auth.ListSessionsResponse {
+	if source == nil {
+		return nil
+	}
+
+	sessions := make([]*auth.Session, 0, len(source.Sessions))
+	for _, session := range source.Sessions {
+		sessions = append(sessions, mapSession(&session))
+	}
+
+	return &auth.ListSessionsResponse{Sessions: sessions}
+}
+
+func mapSession(source *service.SessionInfo) *auth.Session {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.Session{
+		Id:           source.Id,
+		CreatedAt:    timestamppb.New(source.CreatedAt),
+		RefreshedAt:  timestamppb.New(source.RefreshedAt),
+		ExpirationAt: timestamppb.New(source.ExpirationAt),
+		ClientIp:     source.ClientIp,
+		UserAgent:    source.UserAgent,
+	}
+}
+
+func (s *Controller) DeleteSessionById(ctx context.Context, req *auth.DeleteSessionByIdRequest) (*auth.DeleteSessionByIdResponse, error) {
+	ret, err := s.service.DeleteSessionById(ctx, mapDeleteSessionByIdRequest(req))
+
+	return mapDeleteSessionByIdResponse(ret), err
+}
+
+func mapDeleteSessionByIdRequest(source *auth.DeleteSessionByIdRequest) *service.DeleteSessionByIdRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.DeleteSessionByIdRequest{AccessToken: source.AccessToken, SessionId: source.SessionId}
+}
+
+func mapDeleteSessionByIdResponse(source *service.DeleteSessionByIdResponse) *auth.DeleteSessionByIdResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.DeleteSessionByIdResponse{Message: source.Message}
+}
+
+func (s *Controller) ChangeLogin(ctx context.Context, req *auth.ChangeLoginRequest) (*auth.ChangeLoginResponse, error) {
+	ret, err := s.service.ChangeLogin(ctx, mapChangeLoginRequest(req))
+
+	return mapChangeLoginResponse(ret), err
+}
+
+func mapChangeLoginRequest(source *auth.ChangeLoginRequest) *service.ChangeLoginRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.ChangeLoginRequest{AccessToken: source.AccessToken, NewName: source.NewLogin, Password: source.Password}
+}
+
+func mapChangeLoginResponse(source *service.ChangeLoginResponse) *auth.ChangeLoginResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.ChangeLoginResponse{Message: source.Message}
+}
+
+func (s *Controller) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) (*auth.ChangePasswordResponse, error) {
+	ret, err := s.service.ChangePassword(ctx, mapChangePasswordRequest(req))
+
+	return mapChangePasswordResponse(ret), err
+}
+
+func mapChangePasswordRequest(source *auth.ChangePasswordRequest) *service.ChangePasswordRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.ChangePasswordRequest{
+		AccessToken:         source.AccessToken,
+		CurrentPassword:     source.CurrentPassword,
+		NewPassword:         source.NewPassword,
+		RevokeOtherSessions: source.RevokeOtherSessions,
+		RefreshToken:        source.RefreshToken,
+	}
+}
+
+func mapChangePasswordResponse(source *service.ChangePasswordResponse) *auth.ChangePasswordResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.ChangePasswordResponse{Message: source.Message}
+}
+
 func (s *Controller) RefreshTokens(ctx context.Context, req *auth.RefreshTokensRequest) (*auth.RefreshTokensResponse, error) {
-	ret, err := s.service.RefreshTokens(ctx, mapRefreshTokensRequest(req))
+	ret, err := s.service.RefreshTokens(ctx, mapRefreshTokensRequest(ctx, req))
 
 	return mapRefreshTokensResponse(ret), err
 }
 
-func mapRefreshTokensRequest(source *auth.RefreshTokensRequest) *service.RefreshTokensRequest {
+func mapRefreshTokensRequest(ctx context.Context, source *auth.RefreshTokensRequest) *service.RefreshTokensRequest {
 	if source == nil {
 		return nil
 	}
 
-	return &service.RefreshTokensRequest{RefreshToken: source.RefreshToken}
+	clientIp, userAgent := clientInfo(ctx)
+
+	return &service.RefreshTokensRequest{RefreshToken: source.RefreshToken, ClientIp: clientIp, UserAgent: userAgent}
 }
 
 func mapRefreshTokensResponse(source *service.RefreshTokensResponse) *auth.RefreshTokensResponse {
@@ -129,3 +293,139 @@ func mapCheckAccessTokenResponse(source *service.CheckAccessTokenResponse) *auth
 
 	return &auth.CheckAccessTokenResponse{IsActive: source.IsActive}
 }
+
+func (s *Controller) GetCurrentUser(ctx context.Context, req *auth.GetCurrentUserRequest) (*auth.GetCurrentUserResponse, error) {
+	ret, err := s.service.GetCurrentUser(ctx, mapGetCurrentUserRequest(req))
+
+	return mapGetCurrentUserResponse(ret), err
+}
+
+func mapGetCurrentUserRequest(source *auth.GetCurrentUserRequest) *service.GetCurrentUserRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.GetCurrentUserRequest{AccessToken: source.AccessToken}
+}
+
+func mapGetCurrentUserResponse(source *service.GetCurrentUserResponse) *auth.GetCurrentUserResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.GetCurrentUserResponse{Uuid: source.Uuid, Name: source.Name, CreatedAt: timestamppb.New(source.CreatedAt)}
+}
+
+func (s *Controller) RevokeAccessToken(ctx context.Context, req *auth.RevokeAccessTokenRequest) (*auth.RevokeAccessTokenResponse, error) {
+	ret, err := s.service.RevokeAccessToken(ctx, mapRevokeAccessTokenRequest(req))
+
+	return mapRevokeAccessTokenResponse(ret), err
+}
+
+func mapRevokeAccessTokenRequest(source *auth.RevokeAccessTokenRequest) *service.RevokeAccessTokenRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.RevokeAccessTokenRequest{AccessToken: source.AccessToken}
+}
+
+func mapRevokeAccessTokenResponse(source *service.RevokeAccessTokenResponse) *auth.RevokeAccessTokenResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.RevokeAccessTokenResponse{Message: source.Message}
+}
+
+func (s *Controller) RevokeAllAccessTokens(ctx context.Context, req *auth.RevokeAllAccessTokensRequest) (*auth.RevokeAllAccessTokensResponse, error) {
+	ret, err := s.service.RevokeAllAccessTokens(ctx, mapRevokeAllAccessTokensRequest(req))
+
+	return mapRevokeAllAccessTokensResponse(ret), err
+}
+
+func mapRevokeAllAccessTokensRequest(source *auth.RevokeAllAccessTokensRequest) *service.RevokeAllAccessTokensRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.RevokeAllAccessTokensRequest{AccessToken: source.AccessToken}
+}
+
+func mapRevokeAllAccessTokensResponse(source *service.RevokeAllAccessTokensResponse) *auth.RevokeAllAccessTokensResponse {
+	if source == nil {
+		return nil
+	}
+
+	return &auth.RevokeAllAccessTokensResponse{Message: source.Message}
+}
+
+func (s *Controller) Introspect(ctx context.Context, req *auth.IntrospectRequest) (*auth.IntrospectResponse, error) {
+	ret, err := s.service.Introspect(ctx, mapIntrospectRequest(req))
+
+	return mapIntrospectResponse(ret), err
+}
+
+func mapIntrospectRequest(source *auth.IntrospectRequest) *service.IntrospectRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.IntrospectRequest{Token: source.Token, ClientId: source.ClientId, ClientSecret: source.ClientSecret}
+}
+
+func mapIntrospectResponse(source *service.IntrospectResponse) *auth.IntrospectResponse {
+	if source == nil {
+		return nil
+	}
+	if !source.Active {
+		return &auth.IntrospectResponse{Active: false}
+	}
+
+	return &auth.IntrospectResponse{
+		Active:    true,
+		Sub:       source.Subject,
+		Exp:       timestamppb.New(source.ExpirationAt),
+		Iat:       optionalTimestamp(source.IssuedAt),
+		Scope:     source.Scope,
+		ClientId:  source.ClientId,
+		TokenType: source.TokenType,
+		Session:   mapSession(source.Session),
+	}
+}
+
+// optionalTimestamp leaves zero times unset, like tokens issued without iat.
+func optionalTimestamp(source time.Time) *timestamppb.Timestamp {
+	if source.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(source)
+}
+
+func (s *Controller) GetJwks(ctx context.Context, req *auth.GetJwksRequest) (*auth.GetJwksResponse, error) {
+	ret, err := s.service.GetJwks(ctx, mapGetJwksRequest(req))
+
+	return mapGetJwksResponse(ret), err
+}
+
+func mapGetJwksRequest(source *auth.GetJwksRequest) *service.GetJwksRequest {
+	if source == nil {
+		return nil
+	}
+
+	return &service.GetJwksRequest{}
+}
+
+func mapGetJwksResponse(source *service.GetJwksResponse) *auth.GetJwksResponse {
+	if source == nil {
+		return nil
+	}
+
+	keys := make([]*auth.Jwk, 0, len(source.Keys))
+	for _, key := range source.Keys {
+		keys = append(keys, &auth.Jwk{Kty: key.KeyType, Kid: key.KeyId, Use: key.Use, Alg: key.Algorithm, Crv: key.Curve, X: key.X, Y: key.Y, N: key.N, E: key.E})
+	}
+
+	return &auth.GetJwksResponse{Keys: keys}
+}
@@ -9,6 +9,17 @@ type Service interface {
 	Register(ctx context.Context, request *service.RegisterRequest) (*service.RegisterResponse, error)
 	Login(ctx context.Context, request *service.LoginRequest) (*service.LoginResponse, error)
 	DeleteUser(ctx context.Context, request *service.DeleteUserRequest) (*service.DeleteUserResponse, error)
+	DeleteSession(ctx context.Context, request *service.DeleteSessionRequest) (*service.DeleteSessionResponse, error)
+	DeleteAllSessions(ctx context.Context, request *service.DeleteAllSessionsRequest) (*service.DeleteAllSessionsResponse, error)
+	ListSessions(ctx context.Context, request *service.ListSessionsRequest) (*service.ListSessionsResponse, error)
+	DeleteSessionById(ctx context.Context, request *service.DeleteSessionByIdRequest) (*service.DeleteSessionByIdResponse, error)
+	ChangeLogin(ctx context.Context, request *service.ChangeLoginRequest) (*service.ChangeLoginResponse, error)
+	ChangePassword(ctx context.Context, request *service.ChangePasswordRequest) (*service.ChangePasswordResponse, error)
 	RefreshTokens(ctx context.Context, request *service.RefreshTokensRequest) (*service.RefreshTokensResponse, error)
 	CheckAccessToken(ctx context.Context, request *service.CheckAccessTokenRequest) (*service.CheckAccessTokenResponse, error)
+	GetCurrentUser(ctx context.Context, request *service.GetCurrentUserRequest) (*service.GetCurrentUserResponse, error)
+	RevokeAccessToken(ctx context.Context, request *service.RevokeAccessTokenRequest) (*service.RevokeAccessTokenResponse, error)
+	RevokeAllAccessTokens(ctx context.Context, request *service.RevokeAllAccessTokensRequest) (*service.RevokeAllAccessTokensResponse, error)
+	Introspect(ctx context.Context, request *service.IntrospectRequest) (*service.IntrospectResponse, error)
+	GetJwks(ctx context.Context, request *service.GetJwksRequest) (*service.GetJwksResponse, error)
 }
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"grpc-auth/internal/core/services"
 )
 
@@ -16,19 +19,42 @@ var (
 	invariantViolationError *services.InvariantViolationError
 )
 
+// redactedFields hold passwords and client secrets, which must never reach the logs
+var redactedFields = map[protoreflect.Name]bool{
+	"password":        true,
+	"currentPassword": true,
+	"newPassword":     true,
+	"clientSecret":    true,
+}
+
 func ErrorHandlingAndLogging(logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
 		method := info.FullMethod
 		requestUuid := uuid.New().String()
 
-		logger.Infow("begin", "requestUuid", requestUuid, "method", method, "param", req)
+		logger.Infow("begin", "requestUuid", requestUuid, "method", method, "param", redact(req))
 
 		ret, err := next(ctx, req)
 		if err != nil {
 			var st *status.Status
-			if errors.As(err, &invariantViolationError) {
+			var policyError *services.PolicyError
+			var tokenError *services.TokenError
+			var conflictError *services.ConflictError
+			if errors.As(err, &policyError) {
+				st = policyStatus(policyError)
+
+				logger.Infow("end", "requestUuid", requestUuid, "errorCode", st.Code(), "errorMessage", st.Message())
+			} else if errors.As(err, &tokenError) {
+				st = tokenStatus(tokenError)
+
+				logger.Infow("end", "requestUuid", requestUuid, "errorCode", st.Code(), "errorMessage", st.Message(), "errorReason", tokenError.Reason)
+			} else if errors.As(err, &invariantViolationError) {
 				st = status.New(codes.InvalidArgument, err.Error())
 
+				logger.Infow("end", "requestUuid", requestUuid, "errorCode", st.Code(), "errorMessage", st.Message())
+			} else if errors.As(err, &conflictError) {
+				st = status.New(codes.AlreadyExists, err.Error())
+
 				logger.Infow("end", "requestUuid", requestUuid, "errorCode", st.Code(), "errorMessage", st.Message())
 			} else {
 				st = status.New(codes.Internal, fmt.Sprintf("Request UUID: %s. Please send this message to technical support.", requestUuid))
@@ -44,3 +70,65 @@ func ErrorHandlingAndLogging(logger *zap.SugaredLogger) grpc.UnaryServerIntercep
 		}
 	}
 }
+
+// redact returns a copy of the request with the redacted fields replaced, the request itself is passed on unchanged
+func redact(req any) any {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return req
+	}
+
+	var redacted proto.Message
+	message.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if redactedFields[field.Name()] && field.Kind() == protoreflect.StringKind {
+			if redacted == nil {
+				redacted = proto.Clone(message)
+			}
+			redacted.ProtoReflect().Set(field, protoreflect.ValueOfString("[REDACTED]"))
+		}
+
+		return true
+	})
+	if redacted == nil {
+		return req
+	}
+
+	return redacted
+}
+
+// policyStatus attaches every violation as a BadRequest field violation so clients can show all of them at once
+func policyStatus(err *services.PolicyError) *status.Status {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range err.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       err.Field,
+			Description: violation.Description,
+			Reason:      violation.Reason,
+		})
+	}
+
+	detailed, detailsErr := st.WithDetails(badRequest)
+	if detailsErr != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// tokenStatus attaches the reason, so clients can tell an expired token that should be refreshed from a rejected one
+func tokenStatus(err *services.TokenError) *status.Status {
+	code := codes.Unauthenticated
+	if err.Reason == services.TokenMalformed {
+		code = codes.InvalidArgument
+	}
+	st := status.New(code, err.Error())
+
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{Reason: err.Reason, Domain: "grpc-auth"})
+	if detailsErr != nil {
+		return st
+	}
+
+	return detailed
+}
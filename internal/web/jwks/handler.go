@@ -0,0 +1,76 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	service "grpc-auth/internal/core/services/auth"
+	"grpc-auth/internal/core/value-objects"
+	"net/http"
+	"time"
+)
+
+const Path = "/.well-known/jwks.json"
+
+type Service interface {
+	GetJwks(ctx context.Context, request *service.GetJwksRequest) (*service.GetJwksResponse, error)
+}
+
+type document struct {
+	Keys []value_objects.Jwk `json:"keys"`
+}
+
+// Handler serves the public keys as a JWK set. Verifiers may cache it for maxAge, which must not exceed the delay
+// between publishing a new key and signing with it, so a cached document always knows the key of a fresh token.
+type Handler struct {
+	service Service
+	maxAge  time.Duration
+	logger  *zap.SugaredLogger
+}
+
+func NewHandler(service Service, maxAge time.Duration, logger *zap.SugaredLogger) *Handler {
+	return &Handler{service, maxAge, logger}
+}
+
+func NewServer(address string, handler *Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, handler)
+
+	return &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	ret, err := h.service.GetJwks(r.Context(), &service.GetJwksRequest{})
+	if err != nil {
+		h.logger.Errorw("JWKS request failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	// A JWK set must contain an array even when there are no keys yet
+	keys := ret.Keys
+	if keys == nil {
+		keys = []value_objects.Jwk{}
+	}
+
+	body, err := json.Marshal(document{keys})
+	if err != nil {
+		h.logger.Errorw("JWKS request failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,132 @@
+package jwks_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	service "grpc-auth/internal/core/services/auth"
+	"grpc-auth/internal/core/value-objects"
+	"grpc-auth/internal/web/jwks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type mockService struct {
+	mock.Mock
+}
+
+func (s *mockService) GetJwks(ctx context.Context, request *service.GetJwksRequest) (*service.GetJwksResponse, error) {
+	args := s.Called(ctx, request)
+	return args.Get(0).(*service.GetJwksResponse), args.Error(1)
+}
+
+var jwk = value_objects.Jwk{
+	KeyType:   "EC",
+	KeyId:     "2025-04",
+	Use:       "sig",
+	Algorithm: "ES256",
+	Curve:     "P-256",
+	X:         "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+	Y:         "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+}
+
+func TestServeHTTP_Get(t *testing.T) {
+	// Arrange
+	jwksService := &mockService{}
+	handler := jwks.NewHandler(jwksService, time.Hour, zap.NewNop().Sugar())
+	request := httptest.NewRequest(http.MethodGet, jwks.Path, nil)
+	recorder := httptest.NewRecorder()
+
+	jwksService.On("GetJwks", mock.Anything, &service.GetJwksRequest{}).Return(&service.GetJwksResponse{Keys: []value_objects.Jwk{jwk}}, nil)
+
+	// Act
+	handler.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/jwk-set+json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", recorder.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"keys": [{
+		"kty": "EC",
+		"kid": "2025-04",
+		"use": "sig",
+		"alg": "ES256",
+		"crv": "P-256",
+		"x": "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		"y": "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"
+	}]}`, recorder.Body.String())
+	jwksService.AssertExpectations(t)
+}
+
+func TestServeHTTP_NoKeys(t *testing.T) {
+	// Arrange
+	jwksService := &mockService{}
+	handler := jwks.NewHandler(jwksService, time.Hour, zap.NewNop().Sugar())
+	request := httptest.NewRequest(http.MethodGet, jwks.Path, nil)
+	recorder := httptest.NewRecorder()
+
+	jwksService.On("GetJwks", mock.Anything, &service.GetJwksRequest{}).Return(&service.GetJwksResponse{}, nil)
+
+	// Act
+	handler.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"keys": []}`, recorder.Body.String())
+}
+
+func TestServeHTTP_Head(t *testing.T) {
+	// Arrange
+	jwksService := &mockService{}
+	handler := jwks.NewHandler(jwksService, 90*time.Second, zap.NewNop().Sugar())
+	request := httptest.NewRequest(http.MethodHead, jwks.Path, nil)
+	recorder := httptest.NewRecorder()
+
+	jwksService.On("GetJwks", mock.Anything, &service.GetJwksRequest{}).Return(&service.GetJwksResponse{Keys: []value_objects.Jwk{jwk}}, nil)
+
+	// Act
+	handler.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/jwk-set+json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=90", recorder.Header().Get("Cache-Control"))
+}
+
+func TestServeHTTP_MethodNotAllowed(t *testing.T) {
+	// Arrange
+	jwksService := &mockService{}
+	handler := jwks.NewHandler(jwksService, time.Hour, zap.NewNop().Sugar())
+	request := httptest.NewRequest(http.MethodPost, jwks.Path, nil)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, "GET, HEAD", recorder.Header().Get("Allow"))
+	assert.Empty(t, recorder.Header().Get("Cache-Control"))
+	jwksService.AssertNotCalled(t, "GetJwks", mock.Anything, mock.Anything)
+}
+
+func TestServeHTTP_ServiceError(t *testing.T) {
+	// Arrange
+	jwksService := &mockService{}
+	handler := jwks.NewHandler(jwksService, time.Hour, zap.NewNop().Sugar())
+	request := httptest.NewRequest(http.MethodGet, jwks.Path, nil)
+	recorder := httptest.NewRecorder()
+
+	jwksService.On("GetJwks", mock.Anything, &service.GetJwksRequest{}).Return((*service.GetJwksResponse)(nil), errors.New("key ring is not loaded"))
+
+	// Act
+	handler.ServeHTTP(recorder, request)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Cache-Control"))
+}
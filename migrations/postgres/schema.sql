@@ -1,12 +1,53 @@
 CREATE TABLE users (
     uuid UUID PRIMARY KEY,
     created_at TIMESTAMP NOT NULL,
-    name TEXT UNIQUE NOT NULL,
-    password TEXT NOT NULL
+    name TEXT NOT NULL,
+    normalized_name TEXT UNIQUE NOT NULL, -- NFKC and case folded name, so names differing only in case or compatibility forms collide
+    password TEXT NOT NULL,
+    salt TEXT NOT NULL DEFAULT '', -- Empty for users created before per-user salts, they get one on the next login
+    pepper_id TEXT NOT NULL DEFAULT '',
+    password_changed_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    tokens_valid_after TIMESTAMP -- Access tokens issued before are rejected, NULL while all tokens are accepted
 );
 
 CREATE TABLE sessions (
     refresh_token UUID PRIMARY KEY,
     user_uuid UUID REFERENCES users(uuid) ON DELETE CASCADE NOT NULL,
+    expiration_at TIMESTAMP NOT NULL,
+    uuid UUID UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    refreshed_at TIMESTAMP NOT NULL,
+    client_ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL
+);
+
+CREATE INDEX sessions_user_uuid_index ON sessions (user_uuid);
+
+CREATE TABLE password_history (
+    uuid UUID PRIMARY KEY,
+    user_uuid UUID REFERENCES users(uuid) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    password TEXT NOT NULL,
+    salt TEXT NOT NULL,
+    pepper_id TEXT NOT NULL
+);
+
+CREATE INDEX password_history_user_uuid_index ON password_history (user_uuid, created_at);
+
+CREATE TABLE signing_keys (
+    id TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    encrypted_key BYTEA NOT NULL, -- AES-GCM with the master key from the configuration
+    created_at TIMESTAMP NOT NULL,
+    activates_at TIMESTAMP NOT NULL,
+    retires_at TIMESTAMP
+);
+
+CREATE TABLE revoked_tokens (
+    token_uuid UUID PRIMARY KEY, -- The jti claim of the access token
+    user_uuid UUID REFERENCES users(uuid) ON DELETE CASCADE NOT NULL,
+    revoked_at TIMESTAMP NOT NULL,
     expiration_at TIMESTAMP NOT NULL
-);
\ No newline at end of file
+);
+
+CREATE INDEX revoked_tokens_expiration_at_index ON revoked_tokens (expiration_at);
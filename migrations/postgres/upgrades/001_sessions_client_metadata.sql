@@ -0,0 +1,20 @@
+-- Sessions created before get a random uuid and the upgrade time, so they can be listed and deleted like new ones
+BEGIN;
+
+ALTER TABLE sessions
+    ADD COLUMN uuid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    ADD COLUMN refreshed_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC'),
+    ADD COLUMN client_ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE sessions
+    ALTER COLUMN uuid DROP DEFAULT,
+    ALTER COLUMN created_at DROP DEFAULT,
+    ALTER COLUMN refreshed_at DROP DEFAULT,
+    ALTER COLUMN client_ip DROP DEFAULT,
+    ALTER COLUMN user_agent DROP DEFAULT;
+
+CREATE INDEX sessions_user_uuid_index ON sessions (user_uuid);
+
+COMMIT;
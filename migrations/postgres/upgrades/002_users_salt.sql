@@ -0,0 +1,2 @@
+-- Existing users keep the legacy salt until their next login
+ALTER TABLE users ADD COLUMN salt TEXT NOT NULL DEFAULT '';
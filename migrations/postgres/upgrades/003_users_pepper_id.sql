@@ -0,0 +1,2 @@
+-- Existing hashes are not peppered, they get the active pepper on the next login
+ALTER TABLE users ADD COLUMN pepper_id TEXT NOT NULL DEFAULT '';
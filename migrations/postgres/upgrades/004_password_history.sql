@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE password_history (
+    uuid UUID PRIMARY KEY,
+    user_uuid UUID REFERENCES users(uuid) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    password TEXT NOT NULL,
+    salt TEXT NOT NULL,
+    pepper_id TEXT NOT NULL
+);
+
+CREATE INDEX password_history_user_uuid_index ON password_history (user_uuid, created_at);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- When existing users last changed their password is unknown, so their age is counted from the registration
+BEGIN;
+
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMP;
+
+UPDATE users SET password_changed_at = created_at;
+
+ALTER TABLE users
+    ALTER COLUMN password_changed_at SET DEFAULT (now() AT TIME ZONE 'UTC'),
+    ALTER COLUMN password_changed_at SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- Requires PostgreSQL 18 for casefold. The expression mirrors RealUsernamePolicy.Normalize: NFKC, full case folding, NFKC
+BEGIN;
+
+ALTER TABLE users ADD COLUMN normalized_name TEXT;
+
+UPDATE users SET normalized_name = normalize(casefold(normalize(name, NFKC) COLLATE pg_unicode_fast), NFKC);
+
+-- Names differing only in case or compatibility forms could be registered before. Merging them would lock users out,
+-- so the upgrade stops and lists them instead. Rename all but one user of every group and run the script again
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(names, '; ') INTO collisions FROM (
+        SELECT string_agg(name || ' (' || uuid || ')', ', ' ORDER BY created_at) AS names
+        FROM users
+        GROUP BY normalized_name
+        HAVING count(*) > 1
+    ) AS groups;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'usernames collide after normalization: %', collisions;
+    END IF;
+END $$;
+
+ALTER TABLE users
+    ALTER COLUMN normalized_name SET NOT NULL,
+    ADD CONSTRAINT users_normalized_name_key UNIQUE (normalized_name),
+    DROP CONSTRAINT users_name_key;
+
+COMMIT;
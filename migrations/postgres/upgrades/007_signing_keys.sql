@@ -0,0 +1,8 @@
+CREATE TABLE signing_keys (
+    id TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    encrypted_key BYTEA NOT NULL, -- AES-GCM with the master key from the configuration
+    created_at TIMESTAMP NOT NULL,
+    activates_at TIMESTAMP NOT NULL,
+    retires_at TIMESTAMP
+);
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE revoked_tokens (
+    token_uuid UUID PRIMARY KEY, -- The jti claim of the access token
+    user_uuid UUID REFERENCES users(uuid) ON DELETE CASCADE NOT NULL,
+    revoked_at TIMESTAMP NOT NULL,
+    expiration_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX revoked_tokens_expiration_at_index ON revoked_tokens (expiration_at);
+
+COMMIT;
@@ -0,0 +1,2 @@
+-- NULL accepts every token, so existing users keep their issued tokens
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMP;